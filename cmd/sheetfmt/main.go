@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -8,7 +9,10 @@ import (
 	"sheetFmt/internal/excel"
 	"sheetFmt/internal/logger"
 	"sheetFmt/internal/mapping"
-	"strings"
+	"sheetFmt/internal/tabular"
+	"sort"
+	"sync"
+	"time"
 )
 
 func main() {
@@ -26,30 +30,97 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := logger.Init(logger.Config{
+		Level:      cfg.Logging.Level,
+		Format:     cfg.Logging.Format,
+		MaxSizeMB:  cfg.Logging.MaxSizeMB,
+		MaxBackups: cfg.Logging.MaxBackups,
+		MaxAgeDays: cfg.Logging.MaxAgeDays,
+		Redact:     cfg.Logging.Redact,
+	}); err != nil {
+		fmt.Printf("Error initializing logger: %v\n", err)
+		os.Exit(1)
+	}
+
+	stopWatch, err := config.Watch("configs/config.toml", nil)
+	if err != nil {
+		logger.Warn("Config live-reload disabled", "error", err)
+	} else {
+		defer stopWatch()
+	}
+
+	// Every command below is handed config.Current() as it starts, rather
+	// than the cfg captured above. The watcher started above keeps
+	// Current() up to date, but each command holds on to the *Config it
+	// was handed for the rest of its run, so a config.toml edit made while
+	// a command runs is not seen by that command.
 	switch command {
 	case "scan":
-		runScan(cfg)
+		runScan(config.Current(), hasArg(os.Args[2:], "--stream"))
 	case "map":
-		runMapping(cfg)
+		runMapping(config.Current())
 	case "format":
 		if len(os.Args) < 3 {
 			fmt.Println("Error: format command requires input file path")
 			fmt.Println("Usage: sheetfmt format <input_file_path>")
 			return
 		}
-		runFormat(cfg, os.Args[2])
+		runFormat(config.Current(), os.Args[2])
 	case "append-target-headers":
-		runAppendTargetHeaders(cfg)
+		runAppendTargetHeaders(config.Current())
+	case "suggest-mapping":
+		runSuggestMapping(config.Current())
 	case "format-all":
-		runFormatAll(cfg)
+		runFormatAll(config.Current())
+	case "explore":
+		if len(os.Args) < 3 {
+			fmt.Println("Error: explore command requires input file path")
+			fmt.Println("Usage: sheetfmt explore <input_file_path>")
+			return
+		}
+		runExplore(config.Current(), os.Args[2])
 	default:
 		fmt.Printf("Unknown command: %s\n", command)
 		printUsage()
 	}
 }
 
+// formatJob is one unit of work handed to the format-all worker pool: one
+// table (or, for sheets with no native tables, the whole heuristically
+// detected one) within one input file. tableName is the config-pinned
+// Format.TargetTableName when one is set; otherwise it is empty when the
+// sheet declares at most one table, letting excel.FormatFile fall back to
+// its default single-table/heuristic selection. multiTable is true when the
+// sheet declares more than one, so the output gets a per-table suffix.
+type formatJob struct {
+	path       string
+	tableName  string
+	multiTable bool
+}
+
+// manifestEntry is one format job's outcome in results/manifest.json (one
+// file, or one table of a file whose sheet declares several).
+type manifestEntry struct {
+	InputFile      string  `json:"input_file"`
+	OutputFile     string  `json:"output_file"`
+	Success        bool    `json:"success"`
+	Error          string  `json:"error,omitempty"`
+	ElapsedSeconds float64 `json:"elapsed_seconds"`
+	HeaderRow      int     `json:"header_row,omitempty"`
+	DataStartRow   int     `json:"data_start_row,omitempty"`
+	RowsWritten    int     `json:"rows_written,omitempty"`
+}
+
 func runFormatAll(cfg *config.Config) {
-	logger.Info("Starting format-all operation", "input_directory", cfg.Scan.InputDirectory)
+	run, err := logger.StartRun()
+	if err != nil {
+		logger.Error("Failed to start format-all run", "error", err)
+		fmt.Printf("Error starting format-all: %v\n", err)
+		return
+	}
+	defer run.Close()
+
+	run.Info("Starting format-all operation", "input_directory", cfg.Scan.InputDirectory)
 
 	// Check if mapping file exists
 	mappingFilePath := filepath.Join(cfg.Scan.OutputDirectory, "column_mapping.json")
@@ -59,63 +130,107 @@ func runFormatAll(cfg *config.Config) {
 		return
 	}
 
-	// Get all .xlsx files in input directory
-	xlsxFiles, err := getXlsxFiles(cfg.Scan.InputDirectory)
+	// Get all .xlsx/.csv/.tsv files in input directory
+	tabularFiles, err := getTabularFiles(cfg.Scan.InputDirectory)
 	if err != nil {
-		logger.Error("Failed to get Excel files", "error", err)
-		fmt.Printf("Error getting Excel files: %v\n", err)
+		run.Error("Failed to get tabular files", "error", err)
+		fmt.Printf("Error getting tabular files: %v\n", err)
 		return
 	}
 
-	if len(xlsxFiles) == 0 {
-		fmt.Printf("No .xlsx files found in directory: %s\n", cfg.Scan.InputDirectory)
+	if len(tabularFiles) == 0 {
+		fmt.Printf("No .xlsx/.csv/.tsv files found in directory: %s\n", cfg.Scan.InputDirectory)
 		return
 	}
 
-	logger.Info("Found files to format", "file_count", len(xlsxFiles))
+	run.Info("Found files to format", "file_count", len(tabularFiles))
+
+	formatJobs := expandFormatJobs(run, cfg, tabularFiles)
+	run.Info("Expanded files into format jobs", "job_count", len(formatJobs))
 
 	// Create results directory
 	resultsDir := filepath.Join(cfg.Scan.OutputDirectory, "results")
 	err = os.MkdirAll(resultsDir, 0755)
 	if err != nil {
-		logger.Error("Failed to create results directory", "error", err)
+		run.Error("Failed to create results directory", "error", err)
 		fmt.Printf("Error creating results directory: %v\n", err)
 		return
 	}
 
-	// Track statistics
-	successCount := 0
-	errorCount := 0
-
-	// Process each file
-	for i, inputFile := range xlsxFiles {
-		fileName := filepath.Base(inputFile)
-		fmt.Printf("\n[%d/%d] Processing: %s\n", i+1, len(xlsxFiles), fileName)
+	parallelism := cfg.Format.Parallelism
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	if parallelism > len(formatJobs) {
+		parallelism = len(formatJobs)
+	}
+	run.Info("Starting format-all worker pool", "workers", parallelism)
+
+	jobs := make(chan formatJob)
+	results := make(chan manifestEntry)
+
+	var workers sync.WaitGroup
+	for w := 0; w < parallelism; w++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for job := range jobs {
+				results <- formatOneFile(run, cfg, mappingFilePath, job)
+			}
+		}()
+	}
 
-		logger.Info("Processing file", "file", fileName, "progress", fmt.Sprintf("%d/%d", i+1, len(xlsxFiles)))
+	go func() {
+		defer close(jobs)
+		for _, job := range formatJobs {
+			jobs <- job
+		}
+	}()
 
-		err := excel.FormatFile(
-			inputFile,
-			cfg.Format.TargetFormatFile,
-			mappingFilePath,
-			cfg.Format.TargetSheet,
-			cfg.Format.TableEndTolerance,
-			cfg.Format.CleanFormulaOnlyRows,
-		)
+	go func() {
+		workers.Wait()
+		close(results)
+	}()
 
-		if err != nil {
-			logger.Error("Failed to format file", "file", fileName, "error", err)
-			fmt.Printf("❌ Error formatting file: %v\n", err)
-			errorCount++
-		} else {
-			logger.Info("Successfully formatted file", "file", fileName)
+	// A single goroutine owns progress printing, so the [i/N] counter stays
+	// coherent even though results arrive out of submission order.
+	manifest := make([]manifestEntry, 0, len(formatJobs))
+	successCount := 0
+	errorCount := 0
+	done := 0
+	for entry := range results {
+		done++
+		fileName := filepath.Base(entry.InputFile)
+		fmt.Printf("\n[%d/%d] Processed: %s\n", done, len(formatJobs), fileName)
+
+		if entry.Success {
+			run.Info("Successfully formatted file", "file", fileName)
 			fmt.Printf("✓ Successfully formatted\n")
 			successCount++
+		} else {
+			run.Error("Failed to format file", "file", fileName, "error", entry.Error)
+			fmt.Printf("❌ Error formatting file: %s\n", entry.Error)
+			errorCount++
 		}
+		manifest = append(manifest, entry)
+	}
+
+	sort.Slice(manifest, func(i, j int) bool {
+		if manifest[i].InputFile != manifest[j].InputFile {
+			return manifest[i].InputFile < manifest[j].InputFile
+		}
+		return manifest[i].OutputFile < manifest[j].OutputFile
+	})
+
+	manifestPath := filepath.Join(resultsDir, "manifest.json")
+	if manifestBytes, err := json.MarshalIndent(manifest, "", "  "); err != nil {
+		run.Error("Failed to encode manifest", "error", err)
+	} else if err := os.WriteFile(manifestPath, manifestBytes, 0644); err != nil {
+		run.Error("Failed to write manifest", "error", err)
 	}
 
 	// Print summary
-	logger.Info("Format-all operation completed",
+	run.Info("Format-all operation completed",
 		"success_count", successCount,
 		"error_count", errorCount)
 
@@ -127,41 +242,142 @@ func runFormatAll(cfg *config.Config) {
 		fmt.Printf("Check data/problematic directory for files with errors\n")
 	}
 	fmt.Printf("Results saved to: %s\n", resultsDir)
+	fmt.Printf("Manifest: %s\n", manifestPath)
+}
+
+// formatOneFile runs FormatFile for a single job and turns its outcome into
+// a manifestEntry, never panicking the worker goroutine on error.
+func formatOneFile(run *logger.Run, cfg *config.Config, mappingFilePath string, job formatJob) manifestEntry {
+	fileName := filepath.Base(job.path)
+	run.Info("Processing file", "file", fileName, "table", job.tableName)
+
+	start := time.Now()
+	result, err := excel.FormatFile(
+		job.path,
+		cfg.Format.TargetFormatFile,
+		mappingFilePath,
+		cfg.Format.TargetSheet,
+		job.tableName,
+		cfg.Format.TableEndTolerance,
+		cfg.Format.CleanFormulaOnlyRows,
+		cfg.Format.OutputFormat,
+		excel.StyleMode(cfg.Format.StyleMode),
+	)
+
+	entry := manifestEntry{
+		InputFile:      job.path,
+		OutputFile:     excel.OutputPathForTable(job.path, cfg.Format.TargetFormatFile, mappingFilePath, cfg.Format.OutputFormat, job.tableName, job.multiTable),
+		ElapsedSeconds: time.Since(start).Seconds(),
+	}
+	if result != nil {
+		entry.HeaderRow = result.HeaderRow
+		entry.DataStartRow = result.DataStartRow
+		entry.RowsWritten = result.RowsWritten
+	}
+	if err != nil {
+		entry.Error = err.Error()
+		return entry
+	}
+
+	entry.Success = true
+	return entry
+}
+
+// expandFormatJobs turns each input file into one formatJob per table it
+// needs formatting for. A config-pinned Format.TargetTableName yields a
+// single job for that table (marked multiTable when the sheet declares
+// several, matching the per-table suffix FormatFile then gives its output).
+// An input whose sheet declares at most one native table yields a single
+// job (empty tableName, letting excel.FormatFile fall back to its default
+// selection). An XLSX sheet with several tables and no pinned name yields
+// one job per table, so format-all processes every table on the sheet
+// instead of silently picking the first.
+func expandFormatJobs(run *logger.Run, cfg *config.Config, tabularFiles []string) []formatJob {
+	var jobs []formatJob
+	for _, path := range tabularFiles {
+		names, err := detectTableNamesFor(path, cfg.Format.TargetSheet)
+
+		if cfg.Format.TargetTableName != "" {
+			jobs = append(jobs, formatJob{path: path, tableName: cfg.Format.TargetTableName, multiTable: err == nil && len(names) > 1})
+			continue
+		}
+
+		if err != nil {
+			run.Warn("Failed to detect tables, formatting with the default selection", "file", path, "error", err)
+			jobs = append(jobs, formatJob{path: path})
+			continue
+		}
+
+		if len(names) <= 1 {
+			jobs = append(jobs, formatJob{path: path})
+			continue
+		}
+
+		for _, name := range names {
+			jobs = append(jobs, formatJob{path: path, tableName: name, multiTable: true})
+		}
+	}
+	return jobs
 }
 
-// Helper function to get all .xlsx files in a directory
-func getXlsxFiles(dir string) ([]string, error) {
-	var xlsxFiles []string
+// detectTableNamesFor returns the native Excel Tables declared on sheet in
+// path, or nil (not an error) for non-XLSX inputs, which can't declare
+// tables at all.
+func detectTableNamesFor(path, sheet string) ([]string, error) {
+	if format, err := tabular.DetectFormat(path); err != nil || format != tabular.FormatXLSX {
+		return nil, nil
+	}
+
+	editor, err := excel.OpenFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %v", path, err)
+	}
+	defer editor.Close()
+
+	return editor.DetectTableNames(sheet)
+}
+
+// Helper function to get all .xlsx/.csv/.tsv files in a directory
+func getTabularFiles(dir string) ([]string, error) {
+	var tabularFiles []string
 
 	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 
-		if !info.IsDir() && strings.ToLower(filepath.Ext(path)) == ".xlsx" {
-			xlsxFiles = append(xlsxFiles, path)
+		if !info.IsDir() {
+			if _, err := tabular.DetectFormat(path); err == nil {
+				tabularFiles = append(tabularFiles, path)
+			}
 		}
 
 		return nil
 	})
 
-	return xlsxFiles, err
+	return tabularFiles, err
 }
 
 func printUsage() {
 	fmt.Println("SheetFmt - Excel Formatting Tool")
 	fmt.Println("\nUsage:")
-	fmt.Println("  sheetfmt scan                         - Scan Excel files for column names")
+	fmt.Println("  sheetfmt scan [--stream]               - Scan Excel files for column names")
 	fmt.Println("  sheetfmt map                          - Open interactive mapping tool")
 	fmt.Println("  sheetfmt format <input_file>          - Format single Excel file")
 	fmt.Println("  sheetfmt format-all                   - Format all Excel files in input directory")
 	fmt.Println("  sheetfmt append-target-headers        - Add target format headers to target_columns file")
+	fmt.Println("  sheetfmt suggest-mapping               - Pre-fill column_mapping.json with fuzzy-matched guesses")
+	fmt.Println("  sheetfmt explore <input_file>         - Browse a file's rows and map columns in context")
 }
 
-func runScan(cfg *config.Config) {
-	logger.Info("Starting scan operation")
+func runScan(cfg *config.Config, forceStream bool) {
+	logger.Info("Starting scan operation", "stream", forceStream || cfg.Scan.Stream)
 	fmt.Println("\nScanning Excel files for column names...")
-	err := excel.ScanAllColumnsInDirectory(cfg.Scan.InputDirectory, cfg.Scan.OutputDirectory)
+	opts := excel.ScanOptions{
+		Stream:         forceStream || cfg.Scan.Stream,
+		HeaderRowDepth: cfg.Scan.HeaderRowDepth,
+	}
+	err := excel.ScanAllColumnsInDirectoryWithOptions(cfg.Scan.InputDirectory, cfg.Scan.OutputDirectory, opts)
 	if err != nil {
 		logger.Error("Scan operation failed", "error", err)
 		fmt.Printf("Error scanning Excel files: %v\n", err)
@@ -169,6 +385,18 @@ func runScan(cfg *config.Config) {
 	}
 }
 
+// hasArg reports whether flag is present among args, for the handful of
+// boolean CLI flags (like scan's --stream) that don't warrant pulling in
+// the flag package for a single subcommand.
+func hasArg(args []string, flag string) bool {
+	for _, a := range args {
+		if a == flag {
+			return true
+		}
+	}
+	return false
+}
+
 func runMapping(cfg *config.Config) {
 	scannedColumnsFile := filepath.Join(cfg.Scan.OutputDirectory, "scanned_columns")
 	targetColumnsFile := filepath.Join(cfg.Scan.OutputDirectory, "target_columns")
@@ -203,8 +431,22 @@ func runMapping(cfg *config.Config) {
 		ColumnsPerRow: cfg.UI.ColumnsPerRow,
 		RowsPerPage:   cfg.UI.RowsPerPage,
 	}
+	providerConfig := mapping.ProviderConfig{
+		Model:                cfg.AI.Model,
+		Temperature:          cfg.AI.Temperature,
+		Endpoint:             cfg.AI.Endpoint,
+		APIKeyEnvVar:         cfg.AI.APIKeyEnvVar,
+		Timeout:              time.Duration(cfg.AI.TimeoutSeconds) * time.Second,
+		MaxRetries:           cfg.AI.MaxRetries,
+		DisableHybridMapping: cfg.AI.DisableHybridMapping,
+		SynonymsPath:         cfg.AI.SynonymsPath,
+		MatchThreshold:       cfg.AI.MatchThreshold,
+		EmbeddingProvider:    cfg.AI.EmbeddingProvider,
+		EmbeddingThreshold:   cfg.AI.EmbeddingThreshold,
+		EmbeddingCachePath:   cfg.AI.EmbeddingCachePath,
+	}
 
-	err = mapping.RunMappingTUI(scannedColumnsFile, targetColumnsFile, mappingOutputFile, uiConfig)
+	err = mapping.RunMappingTUI(scannedColumnsFile, targetColumnsFile, mappingOutputFile, uiConfig, providerConfig, cfg.AI.Provider)
 	if err != nil {
 		logger.Error("Mapping operation failed", "error", err)
 		fmt.Printf("Error running mapping tool: %v\n", err)
@@ -215,23 +457,54 @@ func runMapping(cfg *config.Config) {
 func runFormat(cfg *config.Config, inputFilePath string) {
 	mappingFilePath := filepath.Join(cfg.Scan.OutputDirectory, "column_mapping.json")
 
-	logger.Info("Starting format operation", "input_file", inputFilePath)
+	run, err := logger.StartRun()
+	if err != nil {
+		logger.Error("Failed to start format run", "error", err)
+		fmt.Printf("Error starting format: %v\n", err)
+		os.Exit(1)
+	}
+	defer run.Close()
+
+	run.Info("Starting format operation", "input_file", inputFilePath)
 
-	err := excel.FormatFile(
+	_, err = excel.FormatFile(
 		inputFilePath,
 		cfg.Format.TargetFormatFile,
 		mappingFilePath,
 		cfg.Format.TargetSheet,
+		cfg.Format.TargetTableName,
 		cfg.Format.TableEndTolerance,
 		cfg.Format.CleanFormulaOnlyRows,
+		cfg.Format.OutputFormat,
+		excel.StyleMode(cfg.Format.StyleMode),
 	)
 	if err != nil {
-		logger.Error("Format operation failed", "error", err)
+		run.Error("Format operation failed", "error", err)
 		fmt.Printf("Error formatting file: %v\n", err)
 		os.Exit(1)
 	}
 }
 
+func runExplore(cfg *config.Config, inputFilePath string) {
+	targetColumnsFile := filepath.Join(cfg.Scan.OutputDirectory, "target_columns")
+	mappingOutputFile := filepath.Join(cfg.Scan.OutputDirectory, "column_mapping.json")
+
+	if err := mapping.CreateDefaultTargetColumnsFile(targetColumnsFile); err != nil {
+		logger.Error("Failed to create target columns file", "error", err)
+		fmt.Printf("Error creating target columns file: %v\n", err)
+		os.Exit(1)
+	}
+
+	logger.Info("Starting explore operation", "input_file", inputFilePath)
+
+	err := mapping.RunExploreTUI(inputFilePath, cfg.Format.TargetSheet, targetColumnsFile, mappingOutputFile)
+	if err != nil {
+		logger.Error("Explore operation failed", "error", err)
+		fmt.Printf("Error running explore tool: %v\n", err)
+		os.Exit(1)
+	}
+}
+
 func runAppendTargetHeaders(cfg *config.Config) {
 	targetColumnsFile := filepath.Join(cfg.Scan.OutputDirectory, "target_columns")
 
@@ -248,3 +521,58 @@ func runAppendTargetHeaders(cfg *config.Config) {
 		os.Exit(1)
 	}
 }
+
+func runSuggestMapping(cfg *config.Config) {
+	scannedColumnsFile := filepath.Join(cfg.Scan.OutputDirectory, "scanned_columns")
+	targetColumnsFile := filepath.Join(cfg.Scan.OutputDirectory, "target_columns")
+	mappingOutputFile := filepath.Join(cfg.Scan.OutputDirectory, "column_mapping.json")
+
+	logger.Info("Starting suggest-mapping operation",
+		"scanned_file", scannedColumnsFile,
+		"target_file", targetColumnsFile,
+		"output_file", mappingOutputFile)
+
+	if _, err := os.Stat(scannedColumnsFile); os.IsNotExist(err) {
+		fmt.Printf("Scanned columns file not found: %s\n", scannedColumnsFile)
+		fmt.Println("Please run 'sheetfmt scan' first to generate scanned columns.")
+		return
+	}
+	if err := mapping.CreateDefaultTargetColumnsFile(targetColumnsFile); err != nil {
+		logger.Error("Failed to create target columns file", "error", err)
+		fmt.Printf("Error creating target columns file: %v\n", err)
+		os.Exit(1)
+	}
+
+	scanned, err := mapping.ReadColumnsFromFile(scannedColumnsFile)
+	if err != nil {
+		logger.Error("Failed to read scanned columns", "error", err)
+		fmt.Printf("Error reading scanned columns: %v\n", err)
+		os.Exit(1)
+	}
+
+	targets, err := mapping.ReadColumnsFromFile(targetColumnsFile)
+	if err != nil {
+		logger.Error("Failed to read target columns", "error", err)
+		fmt.Printf("Error reading target columns: %v\n", err)
+		os.Exit(1)
+	}
+
+	suggestions := mapping.SuggestMappings(scanned, targets, mapping.SuggestOptions{})
+	mc := mapping.MappingConfig{Mappings: suggestions}
+
+	if err := mc.SaveToFile(mappingOutputFile); err != nil {
+		logger.Error("Failed to save suggested mapping", "error", err)
+		fmt.Printf("Error saving suggested mapping: %v\n", err)
+		os.Exit(1)
+	}
+
+	matched := 0
+	for _, m := range suggestions {
+		if !m.IsIgnored {
+			matched++
+		}
+	}
+
+	fmt.Printf("Suggested %d/%d column mappings (review %s before running format-all)\n",
+		matched, len(suggestions), mappingOutputFile)
+}
@@ -60,6 +60,44 @@ func OpenOrCreateFile(filepath string) (*Editor, error) {
 	}, nil
 }
 
+// RowIter streams rows of a sheet one at a time instead of materializing the
+// whole sheet, so callers only ever hold one row in memory.
+type RowIter struct {
+	rows *excelize.Rows
+}
+
+// Next advances the iterator. It returns false once the sheet is exhausted
+// or an error occurred; call Err to distinguish the two.
+func (it *RowIter) Next() bool {
+	return it.rows.Next()
+}
+
+// Columns returns the cell values of the current row.
+func (it *RowIter) Columns() ([]string, error) {
+	return it.rows.Columns()
+}
+
+// Err returns the error, if any, that stopped the iteration.
+func (it *RowIter) Err() error {
+	return it.rows.Error()
+}
+
+// Close releases the underlying row cursor. Callers must always call it,
+// typically via defer.
+func (it *RowIter) Close() error {
+	return it.rows.Close()
+}
+
+// StreamRows opens a row iterator over sheet. Unlike GetAllRows it never
+// loads the whole sheet into memory.
+func (e *Editor) StreamRows(sheet string) (*RowIter, error) {
+	rows, err := e.file.Rows(sheet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open row iterator: %v", err)
+	}
+	return &RowIter{rows: rows}, nil
+}
+
 // ReadColumnValues reads all values from a specific column
 func (e *Editor) ReadColumnValues(sheet, column string) ([]string, error) {
 	rows, err := e.file.GetRows(sheet)
@@ -196,7 +234,20 @@ func indexToColumn(index int) string {
 	return result
 }
 
-// ApplyTargetFormat applies formatting from a target file to the current file
+// ApplyTargetFormat applies formatting from a target file to the current
+// file. It streams the target sheet via StreamRows and writes each cell
+// using the value RowIter.Columns already read off the row, rather than
+// looking the cell back up by address through GetCellFormula/GetCellValue:
+// both of those call into excelize's workSheetReader, which parses and
+// caches the entire worksheet XML into memory on first use, undoing the
+// whole point of streaming. That means a formula cell in the target lands
+// in currentSheet as its last-computed value rather than as a live formula
+// — Columns() only ever exposes a cell's evaluated value, not its formula
+// text. This is a permanent trade-off of streaming, not a stopgap: there is
+// no other path in this package that preserves live formulas, and adding
+// one would mean materializing the whole target sheet again for every
+// caller that doesn't need formulas, which is the cost this function exists
+// to avoid.
 func (e *Editor) ApplyTargetFormat(targetFilePath string, targetSheet, currentSheet string) error {
 	// Open the target format file
 	targetEditor, err := OpenFile(targetFilePath)
@@ -205,65 +256,40 @@ func (e *Editor) ApplyTargetFormat(targetFilePath string, targetSheet, currentSh
 	}
 	defer targetEditor.Close()
 
-	// Get all rows from target sheet to determine the range
-	targetRows, err := targetEditor.GetAllRows(targetSheet)
+	it, err := targetEditor.StreamRows(targetSheet)
 	if err != nil {
 		return fmt.Errorf("failed to read target sheet: %v", err)
 	}
+	defer it.Close()
 
 	// Track cells we've processed
 	processedCount := 0
 	skippedCount := 0
 
-	// Process each potential cell in the target range
-	for rowIndex := 0; rowIndex < len(targetRows); rowIndex++ {
-		// Get the maximum column count for this row and previous rows
-		maxCols := 0
-		for i := 0; i <= rowIndex && i < len(targetRows); i++ {
-			if len(targetRows[i]) > maxCols {
-				maxCols = len(targetRows[i])
-			}
+	rowIndex := -1
+	for it.Next() {
+		rowIndex++
+		row, err := it.Columns()
+		if err != nil {
+			return fmt.Errorf("failed to read target row %d: %v", rowIndex+1, err)
 		}
 
-		for colIndex := 0; colIndex < maxCols; colIndex++ {
+		for colIndex, cellValue := range row {
+			if cellValue == "" {
+				// Cell is empty in target - leave the edited file unchanged
+				skippedCount++
+				continue
+			}
+
 			// Convert column index to Excel column letter (A, B, C, etc.)
 			colLetter := indexToColumn(colIndex)
 			cellAddress := fmt.Sprintf("%s%d", colLetter, rowIndex+1)
 
-			// Check if this cell has a formula first
-			formula, err := targetEditor.file.GetCellFormula(targetSheet, cellAddress)
-			if err != nil {
-				continue // Skip if we can't read the formula
-			}
-
-			if formula != "" {
-				// It's a formula, copy the formula
-				err = e.SetCellFormula(currentSheet, cellAddress, formula)
-				if err != nil {
-					return fmt.Errorf("failed to set formula in cell %s: %v", cellAddress, err)
-				}
-				fmt.Printf("Applied formula to %s: %s\n", cellAddress, formula)
-				processedCount++
-			} else {
-				// Check if the cell has a non-empty value
-				cellValue, err := targetEditor.file.GetCellValue(targetSheet, cellAddress)
-				if err != nil {
-					continue // Skip if we can't read the value
-				}
-
-				// Only apply if the target cell has actual content
-				if cellValue != "" {
-					err = e.SetCellValue(currentSheet, cellAddress, cellValue)
-					if err != nil {
-						return fmt.Errorf("failed to set value in cell %s: %v", cellAddress, err)
-					}
-					fmt.Printf("Applied value to %s: %s\n", cellAddress, cellValue)
-					processedCount++
-				} else {
-					// Cell is empty in target - leave the edited file unchanged
-					skippedCount++
-				}
+			if err := e.SetCellValue(currentSheet, cellAddress, cellValue); err != nil {
+				return fmt.Errorf("failed to set value in cell %s: %v", cellAddress, err)
 			}
+			fmt.Printf("Applied value to %s: %s\n", cellAddress, cellValue)
+			processedCount++
 		}
 	}
 
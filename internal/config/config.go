@@ -4,20 +4,37 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sheetFmt/internal/logger"
 
 	"github.com/BurntSushi/toml"
 )
 
+// defaultSecretEnvVars lists the API key environment variables the
+// registered AI and embedding providers read from, masked by default so
+// a config.toml with no [logging.redact] section still scrubs them.
+var defaultSecretEnvVars = []string{"GEMINI_API_KEY", "OPENAI_API_KEY", "ANTHROPIC_API_KEY"}
+
 type Config struct {
-	Scan   ScanConfig   `toml:"scan"`
-	UI     UIConfig     `toml:"ui"`
-	Format FormatConfig `toml:"format"`
+	Scan    ScanConfig    `toml:"scan"`
+	UI      UIConfig      `toml:"ui"`
+	Format  FormatConfig  `toml:"format"`
+	AI      AIConfig      `toml:"mapping"`
+	Logging LoggingConfig `toml:"logging"`
 }
 
 type ScanConfig struct {
 	InputDirectory  string `toml:"input_directory"`
 	OutputDirectory string `toml:"output_directory"`
+	// Stream forces the scanner's streaming scan path for every file,
+	// regardless of size. The scanner always switches to streaming on its
+	// own for files past its 100MB threshold; this lets the CLI --stream
+	// flag (or a config override) force it for smaller files too.
+	Stream bool `toml:"stream"`
+	// HeaderRowDepth is how many leading rows the streaming scan path reads
+	// per sheet. Defaults to 1; set higher for workbooks with multi-row
+	// headers.
+	HeaderRowDepth int `toml:"header_row_depth"`
 }
 
 type UIConfig struct {
@@ -30,6 +47,87 @@ type FormatConfig struct {
 	TargetSheet          string `toml:"target_sheet"`
 	TableEndTolerance    int    `toml:"table_end_tolerance"`
 	CleanFormulaOnlyRows bool   `toml:"clean_formula_only_rows"`
+	// OutputFormat selects the format FormatFile writes: "xlsx", "csv", or
+	// "both". Defaults to "xlsx" to match the pre-existing behavior.
+	OutputFormat string `toml:"output_format"`
+	// TargetTableName selects which native Excel Table (ListObject) to use
+	// as the target when a sheet declares more than one. Leave empty to
+	// use whichever single table is found, or fall back to the heuristic
+	// header scan when no tables are declared.
+	TargetTableName string `toml:"target_table_name"`
+	// StyleMode controls how much of the template's visual formatting
+	// FormatFile replays onto an XLSX output file: "none" (cell values
+	// only), "header_only", or "full".
+	StyleMode string `toml:"style_mode"`
+	// Parallelism is the number of worker goroutines format-all uses to
+	// process input files concurrently. Defaults to runtime.NumCPU().
+	Parallelism int `toml:"parallelism"`
+}
+
+// AIConfig selects and tunes the AI backend mapping.NewProvider constructs
+// for column-mapping suggestions ([mapping] in config.toml, named AI here
+// since mapping.MappingConfig already names the column-mapping file
+// format). Model, Endpoint, and APIKeyEnvVar may be left blank: each
+// provider falls back to its own default when so.
+type AIConfig struct {
+	// Provider is the registered provider name: "gemini", "openai",
+	// "anthropic", or "ollama".
+	Provider string `toml:"provider"`
+	// Model overrides the provider's default model name.
+	Model string `toml:"model"`
+	// Temperature is passed to providers that support it.
+	Temperature float64 `toml:"temperature"`
+	// Endpoint overrides the provider's default API base URL or host.
+	Endpoint string `toml:"endpoint"`
+	// APIKeyEnvVar overrides the environment variable a provider reads its
+	// API key from (e.g. "OPENAI_API_KEY"). Unused by ollama, which needs
+	// no key.
+	APIKeyEnvVar string `toml:"api_key_env_var"`
+	// TimeoutSeconds bounds a single request to the provider's API.
+	TimeoutSeconds int `toml:"timeout_seconds"`
+	// MaxRetries is how many additional attempts a provider makes after a
+	// failed request, with exponential backoff between attempts.
+	MaxRetries int `toml:"max_retries"`
+
+	// DisableHybridMapping skips the deterministic/embedding pre-pass and
+	// sends every column straight to the LLM prompt.
+	DisableHybridMapping bool `toml:"disable_hybrid_mapping"`
+	// SynonymsPath overrides where the hybrid pre-pass loads its
+	// user-editable synonym dictionary from. Defaults to
+	// "configs/synonyms.toml".
+	SynonymsPath string `toml:"synonyms_path"`
+	// MatchThreshold overrides the hybrid pre-pass's deterministic-score
+	// floor (0-1) for auto-accepting a match without calling the LLM.
+	MatchThreshold float64 `toml:"match_threshold"`
+	// EmbeddingProvider names the registered EmbeddingProvider the hybrid
+	// pre-pass's embedding stage should use ("gemini", "openai", or
+	// "ollama"). Empty disables that stage.
+	EmbeddingProvider string `toml:"embedding_provider"`
+	// EmbeddingThreshold overrides the hybrid pre-pass's cosine-similarity
+	// floor (0-1) for auto-accepting an embedding match.
+	EmbeddingThreshold float64 `toml:"embedding_threshold"`
+	// EmbeddingCachePath overrides where the embedding stage persists
+	// vectors across runs. Defaults to "configs/embedding_cache.db".
+	EmbeddingCachePath string `toml:"embedding_cache_path"`
+}
+
+// LoggingConfig selects and tunes logger.Init's level, output format, and
+// rotation of logs/sheetfmt.log ([logging] in config.toml).
+type LoggingConfig struct {
+	// Level is "debug", "info", "warn", or "error". Defaults to "info".
+	Level string `toml:"level"`
+	// Format is "text" or "json". Defaults to "text".
+	Format string `toml:"format"`
+	// MaxSizeMB is the size in megabytes a log file reaches before it's
+	// rotated. Defaults to 100.
+	MaxSizeMB int `toml:"max_size_mb"`
+	// MaxBackups is how many rotated log files are kept. Defaults to 5.
+	MaxBackups int `toml:"max_backups"`
+	// MaxAgeDays is how many days a rotated log file is kept. Defaults to 28.
+	MaxAgeDays int `toml:"max_age_days"`
+	// Redact configures logger.Init's redaction middleware
+	// ([logging.redact] in config.toml).
+	Redact logger.RedactConfig `toml:"redact"`
 }
 
 // LoadConfig loads configuration from the specified config file path
@@ -47,6 +145,7 @@ func LoadConfig(configPath string) (*Config, error) {
 			Scan: ScanConfig{
 				InputDirectory:  "data/input",
 				OutputDirectory: "data/output",
+				HeaderRowDepth:  1,
 			},
 			UI: UIConfig{
 				ColumnsPerRow: 6,
@@ -57,6 +156,26 @@ func LoadConfig(configPath string) (*Config, error) {
 				TargetSheet:          "Sheet1",
 				TableEndTolerance:    1,
 				CleanFormulaOnlyRows: true,
+				OutputFormat:         "xlsx",
+				StyleMode:            "none",
+				Parallelism:          runtime.NumCPU(),
+			},
+			AI: AIConfig{
+				Provider:       "gemini",
+				Temperature:    0.1,
+				TimeoutSeconds: 90,
+				MaxRetries:     2,
+			},
+			Logging: LoggingConfig{
+				Level:      "info",
+				Format:     "text",
+				MaxSizeMB:  100,
+				MaxBackups: 5,
+				MaxAgeDays: 28,
+				Redact: logger.RedactConfig{
+					SecretEnvVars: defaultSecretEnvVars,
+					MaxAttrLen:    4096,
+				},
 			},
 		}
 
@@ -66,17 +185,30 @@ func LoadConfig(configPath string) (*Config, error) {
 		}
 
 		logger.Info("Created default config file", "path", configPath)
+		current.Store(defaultConfig)
 		return defaultConfig, nil
 	}
 
-	// Load existing config
-	var config Config
+	// Load existing config. AI.Temperature is pre-seeded to -1 so
+	// applyDefaults can tell "omitted from the TOML" apart from an
+	// explicit temperature = 0.0, which is a legitimate setting for
+	// deterministic extraction and must survive untouched.
+	config := Config{AI: AIConfig{Temperature: -1}}
 	_, err := toml.DecodeFile(configPath, &config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load config file %s: %v", configPath, err)
 	}
 
-	// Set defaults if missing
+	applyDefaults(&config)
+
+	logger.Info("Loaded configuration", "path", configPath)
+	current.Store(&config)
+	return &config, nil
+}
+
+// applyDefaults fills in the zero-valued fields LoadConfig and Watch's
+// reload both tolerate a config.toml omitting.
+func applyDefaults(config *Config) {
 	if config.UI.ColumnsPerRow == 0 {
 		config.UI.ColumnsPerRow = 6
 	}
@@ -92,9 +224,59 @@ func LoadConfig(configPath string) (*Config, error) {
 	if config.Format.TableEndTolerance == 0 {
 		config.Format.TableEndTolerance = 1
 	}
-
-	logger.Info("Loaded configuration", "path", configPath)
-	return &config, nil
+	switch config.Format.OutputFormat {
+	case "xlsx", "csv", "both":
+	default:
+		config.Format.OutputFormat = "xlsx"
+	}
+	switch config.Format.StyleMode {
+	case "none", "header_only", "full":
+	default:
+		config.Format.StyleMode = "none"
+	}
+	if config.Format.Parallelism < 1 {
+		config.Format.Parallelism = runtime.NumCPU()
+	}
+	if config.Scan.HeaderRowDepth < 1 {
+		config.Scan.HeaderRowDepth = 1
+	}
+	if config.AI.Provider == "" {
+		config.AI.Provider = "gemini"
+	}
+	if config.AI.Temperature < 0 {
+		config.AI.Temperature = 0.1
+	}
+	if config.AI.TimeoutSeconds < 1 {
+		config.AI.TimeoutSeconds = 90
+	}
+	if config.AI.MaxRetries < 0 {
+		config.AI.MaxRetries = 2
+	}
+	switch config.Logging.Level {
+	case "debug", "info", "warn", "warning", "error":
+	default:
+		config.Logging.Level = "info"
+	}
+	switch config.Logging.Format {
+	case "text", "json":
+	default:
+		config.Logging.Format = "text"
+	}
+	if config.Logging.MaxSizeMB <= 0 {
+		config.Logging.MaxSizeMB = 100
+	}
+	if config.Logging.MaxBackups <= 0 {
+		config.Logging.MaxBackups = 5
+	}
+	if config.Logging.MaxAgeDays <= 0 {
+		config.Logging.MaxAgeDays = 28
+	}
+	if len(config.Logging.Redact.SecretEnvVars) == 0 {
+		config.Logging.Redact.SecretEnvVars = defaultSecretEnvVars
+	}
+	if config.Logging.Redact.MaxAttrLen <= 0 {
+		config.Logging.Redact.MaxAttrLen = 4096
+	}
 }
 
 // SaveConfig saves configuration to the specified config file path
@@ -0,0 +1,184 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sheetFmt/internal/logger"
+	"sync/atomic"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/fsnotify/fsnotify"
+)
+
+// current holds the most recently loaded (or hot-reloaded) Config.
+// LoadConfig populates it on startup; Watch's reload swaps it on every
+// valid edit of the watched file.
+var current atomic.Pointer[Config]
+
+// Current returns the most recently loaded Config. Callers that run for
+// more than an instant (the mapping/explore TUIs, a long scan or
+// format-all) should call this at the point of use rather than holding
+// on to a *Config from LoadConfig, so a Watch-triggered reload reaches
+// them without a restart.
+func Current() *Config {
+	return current.Load()
+}
+
+// providerAPIKeyEnvVar names the environment variable each registered AI
+// provider reads its key from by default, mirroring the providers'
+// own *DefaultAPIKeyEnvVar constants in internal/mapping. Duplicated here
+// rather than imported to keep config free of a dependency on mapping.
+var providerAPIKeyEnvVar = map[string]string{
+	"gemini":    "GEMINI_API_KEY",
+	"openai":    "OPENAI_API_KEY",
+	"anthropic": "ANTHROPIC_API_KEY",
+}
+
+// validateConfig rejects a Config Watch should never swap in: an input
+// directory that doesn't exist, an output directory that can't be
+// created, a negative table-end tolerance, or an AI provider whose
+// credentials aren't present in the environment. ollama needs no key, so
+// it's exempt.
+func validateConfig(cfg *Config) error {
+	if _, err := os.Stat(cfg.Scan.InputDirectory); err != nil {
+		return fmt.Errorf("scan.input_directory %q: %v", cfg.Scan.InputDirectory, err)
+	}
+	if err := os.MkdirAll(cfg.Scan.OutputDirectory, 0755); err != nil {
+		return fmt.Errorf("scan.output_directory %q is not creatable: %v", cfg.Scan.OutputDirectory, err)
+	}
+	if cfg.Format.TableEndTolerance < 0 {
+		return fmt.Errorf("format.table_end_tolerance must be >= 0, got %d", cfg.Format.TableEndTolerance)
+	}
+
+	if envVar, ok := providerAPIKeyEnvVar[cfg.AI.Provider]; ok {
+		if cfg.AI.APIKeyEnvVar != "" {
+			envVar = cfg.AI.APIKeyEnvVar
+		}
+		if os.Getenv(envVar) == "" {
+			return fmt.Errorf("mapping.provider %q requires credentials: %s is not set", cfg.AI.Provider, envVar)
+		}
+	}
+
+	return nil
+}
+
+// Watch monitors path for writes, re-parsing, defaulting, and validating
+// the file after a 500ms debounce (so a series of editor-save events
+// coalesces into one reload). A config that fails to parse or validate
+// is rejected and Current() keeps returning the prior, known-good
+// Config; otherwise the new Config atomically replaces it and onChange
+// (which may be nil) is called with the old and new Config. Returns a
+// stop function that must be called to release the underlying watcher.
+func Watch(path string, onChange func(oldCfg, newCfg *Config)) (func() error, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config watcher: %v", err)
+	}
+
+	dir := filepath.Dir(path)
+	if err := watcher.Add(dir); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("failed to watch %s: %v", dir, err)
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("failed to resolve %s: %v", path, err)
+	}
+
+	go func() {
+		var debounce *time.Timer
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				eventPath, err := filepath.Abs(event.Name)
+				if err != nil || eventPath != absPath {
+					continue
+				}
+				if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+					continue
+				}
+
+				if debounce != nil {
+					debounce.Stop()
+				}
+				debounce = time.AfterFunc(500*time.Millisecond, func() {
+					reload(path, onChange)
+				})
+
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				logger.Warn("Config watcher error", "path", path, "error", err)
+			}
+		}
+	}()
+
+	return watcher.Close, nil
+}
+
+// reload re-parses path and swaps it into Current() if it's valid,
+// rolling back (by simply not swapping) and logging an error otherwise,
+// so a broken edit never crashes callers reading through Current().
+func reload(path string, onChange func(oldCfg, newCfg *Config)) {
+	// Pre-seed AI.Temperature the same way LoadConfig does, so a reload
+	// tells an omitted temperature apart from an explicit 0.0 too.
+	next := Config{AI: AIConfig{Temperature: -1}}
+	if _, err := toml.DecodeFile(path, &next); err != nil {
+		logger.Error("Config reload failed to parse, keeping prior config", "path", path, "error", err)
+		return
+	}
+	applyDefaults(&next)
+
+	if err := validateConfig(&next); err != nil {
+		logger.Error("Config reload failed validation, keeping prior config", "path", path, "error", err)
+		return
+	}
+
+	old := current.Load()
+	current.Store(&next)
+
+	logger.Info("Config reloaded", "path", path, "diff", diffConfig(old, &next))
+	if onChange != nil {
+		onChange(old, &next)
+	}
+}
+
+// diffConfig returns one "Section.Field: old -> new" string per leaf
+// field that differs between oldCfg and newCfg, for reload's log line.
+func diffConfig(oldCfg, newCfg *Config) []string {
+	var diffs []string
+	if oldCfg == nil || newCfg == nil {
+		return diffs
+	}
+	diffStructFields("", reflect.ValueOf(*oldCfg), reflect.ValueOf(*newCfg), &diffs)
+	return diffs
+}
+
+func diffStructFields(prefix string, oldVal, newVal reflect.Value, diffs *[]string) {
+	t := oldVal.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+		name := prefix + field.Name
+		ov, nv := oldVal.Field(i), newVal.Field(i)
+
+		if ov.Kind() == reflect.Struct {
+			diffStructFields(name+".", ov, nv, diffs)
+			continue
+		}
+		if !reflect.DeepEqual(ov.Interface(), nv.Interface()) {
+			*diffs = append(*diffs, fmt.Sprintf("%s: %v -> %v", name, ov.Interface(), nv.Interface()))
+		}
+	}
+}
@@ -2,7 +2,8 @@ package excel
 
 import (
 	"fmt"
-	"strings"
+
+	"sheetFmt/internal/xlsxheader"
 
 	"github.com/xuri/excelize/v2"
 )
@@ -24,62 +25,112 @@ func OpenFile(filepath string) (*Editor, error) {
 	}, nil
 }
 
-// DetectHeaderRow finds the row containing column headers using the strategy:
-// Find rightmost column with data, then find first row with data in that column
-func (e *Editor) DetectHeaderRow(sheet string) (int, error) {
-	rows, err := e.file.GetRows(sheet)
-	if err != nil {
-		return 0, fmt.Errorf("failed to get rows: %v", err)
-	}
+// RowIter streams rows of a sheet one at a time instead of materializing the
+// whole sheet, so callers only ever hold one row in memory.
+type RowIter struct {
+	rows *excelize.Rows
+}
 
-	if len(rows) == 0 {
-		return 1, nil // Default to row 1 if no data
-	}
+// Next advances the iterator. It returns false once the sheet is exhausted
+// or an error occurred; call Err to distinguish the two.
+func (it *RowIter) Next() bool {
+	return it.rows.Next()
+}
 
-	// Find the rightmost column with data across all rows
-	rightmostCol := 0
-	for _, row := range rows {
-		for colIdx := len(row) - 1; colIdx >= 0; colIdx-- {
-			if strings.TrimSpace(row[colIdx]) != "" {
-				if colIdx+1 > rightmostCol {
-					rightmostCol = colIdx + 1 // Convert to 1-based column
-				}
-				break // Found the rightmost data in this row
-			}
-		}
-	}
+// Columns returns the cell values of the current row.
+func (it *RowIter) Columns() ([]string, error) {
+	return it.rows.Columns()
+}
 
-	if rightmostCol == 0 {
-		return 1, nil // Default to row 1 if no data
-	}
+// Err returns the error, if any, that stopped the iteration.
+func (it *RowIter) Err() error {
+	return it.rows.Error()
+}
 
-	// Now find the first row that has data in the rightmost column
-	for rowIdx, row := range rows {
-		if len(row) >= rightmostCol && strings.TrimSpace(row[rightmostCol-1]) != "" {
-			return rowIdx + 1, nil // Convert to 1-based row number
-		}
+// Close releases the underlying row cursor. Callers must always call it,
+// typically via defer.
+func (it *RowIter) Close() error {
+	return it.rows.Close()
+}
+
+// StreamRows opens a row iterator over sheet. Unlike GetAllRows it never
+// loads the whole sheet into memory; each call to Next/Columns only reads
+// the current row off the underlying zip stream.
+func (e *Editor) StreamRows(sheet string) (*RowIter, error) {
+	rows, err := e.file.Rows(sheet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open row iterator: %v", err)
 	}
+	return &RowIter{rows: rows}, nil
+}
 
-	return 1, nil // Default to row 1 if not found
+// DetectHeaderRow finds the row containing column headers using the strategy:
+// find the rightmost column with data anywhere in the sheet, then find the
+// first row that reaches it. It streams the sheet once instead of loading
+// every row into memory. The heuristic itself lives in internal/xlsxheader
+// so internal/tabular can share it; see that package's doc comment.
+func (e *Editor) DetectHeaderRow(sheet string) (int, error) {
+	return xlsxheader.DetectHeaderRow(e.file, sheet)
 }
 
-// GetColumnHeaders returns all column headers from the detected header row
+// GetColumnHeaders returns all column headers from the detected header row.
+// It streams the sheet twice (once to detect the header row, once to read
+// it) rather than materializing the whole sheet.
 func (e *Editor) GetColumnHeaders(sheet string) ([]string, error) {
 	headerRow, err := e.DetectHeaderRow(sheet)
 	if err != nil {
 		return nil, fmt.Errorf("failed to detect header row: %v", err)
 	}
 
-	rows, err := e.file.GetRows(sheet)
+	it, err := e.StreamRows(sheet)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get rows: %v", err)
+		return nil, err
 	}
-
-	if len(rows) < headerRow {
-		return []string{}, nil
+	defer it.Close()
+
+	rowIdx := 0
+	for it.Next() {
+		rowIdx++
+		row, err := it.Columns()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read row %d: %v", rowIdx, err)
+		}
+		if rowIdx == headerRow {
+			return row, nil
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, fmt.Errorf("failed to stream rows: %v", err)
 	}
 
-	return rows[headerRow-1], nil // Convert back to 0-based index
+	return []string{}, nil
+}
+
+// CreateNewFile creates a new Excel file in memory
+func CreateNewFile() *Editor {
+	return &Editor{file: excelize.NewFile()}
+}
+
+// AddSheet creates a new sheet
+func (e *Editor) AddSheet(sheetName string) error {
+	_, err := e.file.NewSheet(sheetName)
+	return err
+}
+
+// SetCellValue sets a value in a specific cell
+func (e *Editor) SetCellValue(sheet, cell string, value interface{}) error {
+	return e.file.SetCellValue(sheet, cell, value)
+}
+
+// SetCellFormula sets a formula for a specific cell
+func (e *Editor) SetCellFormula(sheet, cell, formula string) error {
+	return e.file.SetCellFormula(sheet, cell, formula)
+}
+
+// SaveAs saves the workbook to filepath
+func (e *Editor) SaveAs(filepath string) error {
+	e.filepath = filepath
+	return e.file.SaveAs(filepath)
 }
 
 // GetSheetNames returns all sheet names in the workbook
@@ -92,7 +143,8 @@ func (e *Editor) Close() error {
 	return e.file.Close()
 }
 
-// GetAllRows returns all rows from a sheet
+// GetAllRows returns all rows from a sheet. Prefer StreamRows for large
+// sheets; this remains for callers that genuinely need random access.
 func (e *Editor) GetAllRows(sheet string) ([][]string, error) {
 	return e.file.GetRows(sheet)
 }
@@ -3,68 +3,119 @@ package excel
 import (
 	"fmt"
 	"os"
-	"os/exec"
+
 	"sheetFmt/internal/logger"
-	"strconv"
 )
 
-// FormatFile formats an entire Excel file with all its sheets using Python script
-func FormatFile(inputFilePath, targetFilePath, mappingFilePath, targetSheet string, tableEndTolerance int, cleanFormulaOnlyRows bool) error {
-	// Validate input files
-	if err := validateInputFiles(inputFilePath, targetFilePath, mappingFilePath); err != nil {
-		return err
-	}
-
-	logger.Info("Starting file format",
-		"input", inputFilePath,
-		"target", targetFilePath,
-		"mapping", mappingFilePath,
-		"target_sheet", targetSheet)
-
-	// Get the path to the Python script
-	scriptPath := "internal/format/format_excel.py"
+// FormatResult carries the per-file facts a caller needs to report on a
+// format run (e.g. to build a machine-readable manifest) without re-deriving
+// them from the input file itself.
+type FormatResult struct {
+	HeaderRow    int
+	DataStartRow int
+	RowsWritten  int
+}
 
-	// Check if Python script exists
-	if _, err := os.Stat(scriptPath); os.IsNotExist(err) {
-		return fmt.Errorf("python formatting script not found: %s", scriptPath)
+// FormatFile formats an entire Excel file, reordering its columns to match
+// targetFilePath per mappingFilePath. It's a thin shim over
+// FormatFileNative, kept so existing callers don't need to change; formatting
+// used to shell out to a Python script, which made deployment fragile
+// (Python had to be installed, PYTHONIOENCODING set, the script bundled at a
+// hardcoded relative path). FormatFileNative does the same work natively.
+//
+// outputFormat is the config.FormatConfig.OutputFormat value ("xlsx", "csv",
+// or "both"); an empty or unrecognized value falls back to inferring the
+// sink from the input/target file extensions. "both" writes an .xlsx output
+// and a .csv output from the same pass. styleMode is the
+// config.FormatConfig.StyleMode value; it's meaningless for a CSV/TSV sink,
+// which carries no cell styling, so it only affects the xlsx side of "both".
+func FormatFile(inputFilePath, targetFilePath, mappingFilePath, targetSheet, targetTableName string, tableEndTolerance int, cleanFormulaOnlyRows bool, outputFormat string, styleMode StyleMode) (*FormatResult, error) {
+	switch outputFormat {
+	case "csv":
+		return FormatFileNative(inputFilePath, targetFilePath, mappingFilePath, targetSheet, targetTableName, tableEndTolerance, cleanFormulaOnlyRows, SinkCSV, styleMode)
+	case "both":
+		result, err := FormatFileNative(inputFilePath, targetFilePath, mappingFilePath, targetSheet, targetTableName, tableEndTolerance, cleanFormulaOnlyRows, SinkXLSX, styleMode)
+		if err != nil {
+			return result, err
+		}
+		if _, err := FormatFileNative(inputFilePath, targetFilePath, mappingFilePath, targetSheet, targetTableName, tableEndTolerance, cleanFormulaOnlyRows, SinkCSV, styleMode); err != nil {
+			return result, err
+		}
+		return result, nil
+	case "xlsx":
+		return FormatFileNative(inputFilePath, targetFilePath, mappingFilePath, targetSheet, targetTableName, tableEndTolerance, cleanFormulaOnlyRows, SinkXLSX, styleMode)
+	default:
+		return FormatFileNative(inputFilePath, targetFilePath, mappingFilePath, targetSheet, targetTableName, tableEndTolerance, cleanFormulaOnlyRows, "", styleMode)
 	}
+}
 
-	// Convert bool to string for Python
-	cleanFlag := "false"
-	if cleanFormulaOnlyRows {
-		cleanFlag = "true"
+// detectHeaderRow finds the header and data-start row for sheet, preferring
+// a native Excel Table over the heuristic header scan. When the sheet
+// declares no tables, it falls back to Editor.DetectHeaderRow. When it
+// declares more than one table, targetTableName selects which to use.
+// tableCount is the number of native tables detected on sheet (0 when
+// falling back to the heuristic); callers use it to decide whether an
+// output needs a per-table suffix.
+func detectHeaderRow(inputFilePath, sheet, targetTableName string) (headerRow, dataStartRow, tableCount int, err error) {
+	editor, err := OpenFile(inputFilePath)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to open input file: %v", err)
 	}
+	defer editor.Close()
 
-	// Run Python script for all sheets
-	cmd := exec.Command("python", scriptPath, inputFilePath, targetFilePath, mappingFilePath, targetSheet, strconv.Itoa(tableEndTolerance), cleanFlag)
+	tables, err := editor.DetectTables(sheet)
+	if err != nil {
+		logger.Warn("Failed to read native tables, falling back to heuristic", "input", inputFilePath, "error", err)
+		tables = nil
+	}
 
-	// Set environment to use UTF-8 encoding for Python
-	cmd.Env = append(os.Environ(), "PYTHONIOENCODING=utf-8")
+	switch {
+	case len(tables) == 0:
+		row, err := editor.DetectHeaderRow(sheet)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		return row, row + 1, 0, nil
 
-	// Capture output
-	output, err := cmd.CombinedOutput()
+	case targetTableName != "":
+		for _, t := range tables {
+			if t.Name == targetTableName {
+				if t.HeaderRowCount == 0 {
+					return 0, 0, 0, fmt.Errorf("table %q on sheet %s declares no header row (headerRowCount=0); name-based column mapping requires one", t.Name, sheet)
+				}
+				return t.HeaderRow, t.DataStartRow, len(tables), nil
+			}
+		}
+		return 0, 0, 0, fmt.Errorf("no table named %q on sheet %s", targetTableName, sheet)
 
-	if err != nil {
-		logger.Error("Python formatting failed", "error", err, "output", string(output))
-		return fmt.Errorf("python formatting failed: %v", err)
+	default:
+		// No explicit selection; use the first declared table.
+		first := tables[0]
+		if first.HeaderRowCount == 0 {
+			return 0, 0, 0, fmt.Errorf("table %q on sheet %s declares no header row (headerRowCount=0); name-based column mapping requires one", first.Name, sheet)
+		}
+		return first.HeaderRow, first.DataStartRow, len(tables), nil
 	}
-
-	logger.Info("File formatted successfully", "input", inputFilePath)
-	fmt.Printf("%s", string(output))
-	return nil
 }
 
-// validateInputFiles validates that all required input files exist
+// validateInputFiles validates that all required input files exist. It's a
+// thin shim over validateInputFilesFS using the real filesystem.
 func validateInputFiles(inputFilePath, targetFilePath, mappingFilePath string) error {
-	if _, err := os.Stat(inputFilePath); os.IsNotExist(err) {
+	return validateInputFilesFS(OSFS{}, inputFilePath, targetFilePath, mappingFilePath)
+}
+
+// validateInputFilesFS is validateInputFiles with the existence checks
+// routed through fsys instead of os.Stat directly.
+func validateInputFilesFS(fsys FS, inputFilePath, targetFilePath, mappingFilePath string) error {
+	if _, err := fsys.Stat(inputFilePath); os.IsNotExist(err) {
 		return fmt.Errorf("input file not found: %s", inputFilePath)
 	}
 
-	if _, err := os.Stat(mappingFilePath); os.IsNotExist(err) {
+	if _, err := fsys.Stat(mappingFilePath); os.IsNotExist(err) {
 		return fmt.Errorf("mapping file not found: %s", mappingFilePath)
 	}
 
-	if _, err := os.Stat(targetFilePath); os.IsNotExist(err) {
+	if _, err := fsys.Stat(targetFilePath); os.IsNotExist(err) {
 		return fmt.Errorf("target format file not found: %s", targetFilePath)
 	}
 
@@ -0,0 +1,411 @@
+package excel
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"sheetFmt/internal/logger"
+	"sheetFmt/internal/tabular"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// FormatFileNative reorders inputFilePath's columns to match targetFilePath's
+// column order, following mappingFilePath, entirely in Go. It writes the
+// result next to mappingFilePath (the scan output directory) under
+// inputFilePath's own base name (with sink's extension substituted), the
+// same directory the format-all manifest already expects each job's
+// OutputFile to live in.
+//
+// sink forces the output format; pass "" to infer it from the input/target
+// file extensions instead (see resolveSinkKind). Reading is XLSX-only for
+// now — inputFilePath must be a native Excel Table or heuristically
+// detectable sheet; CSV/TSV sources are rejected with a clear error rather
+// than silently producing garbage.
+func FormatFileNative(inputFilePath, targetFilePath, mappingFilePath, targetSheet, targetTableName string, tableEndTolerance int, cleanFormulaOnlyRows bool, sink SinkKind, styleMode StyleMode) (*FormatResult, error) {
+	if err := validateInputFiles(inputFilePath, targetFilePath, mappingFilePath); err != nil {
+		return nil, err
+	}
+
+	source, err := detectSourceKind(inputFilePath)
+	if err != nil {
+		return nil, err
+	}
+	if source != SourceXLSX {
+		return nil, fmt.Errorf("FormatFileNative does not yet support %s input files: %s", source, inputFilePath)
+	}
+
+	sink = resolveSinkKind(inputFilePath, targetFilePath, sink)
+
+	logger.Info("Starting native file format",
+		"input", inputFilePath,
+		"target", targetFilePath,
+		"mapping", mappingFilePath,
+		"target_sheet", targetSheet,
+		"sink", sink)
+
+	mappingConfig, err := loadMapping(OSFS{}, mappingFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load mapping: %v", err)
+	}
+	targetToScanned := invertMapping(mappingConfig)
+
+	input, err := OpenFile(inputFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open input file: %v", err)
+	}
+	defer input.Close()
+
+	headerRow, dataStartRow, tableCount, err := detectHeaderRow(inputFilePath, targetSheet, targetTableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect header row: %v", err)
+	}
+	result := &FormatResult{HeaderRow: headerRow, DataStartRow: dataStartRow}
+
+	scannedHeaders, err := input.readRowAt(targetSheet, headerRow)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scanned headers: %v", err)
+	}
+	scannedIndex := make(map[string]int, len(scannedHeaders))
+	for i, header := range scannedHeaders {
+		scannedIndex[strings.TrimSpace(header)] = i
+	}
+
+	target, err := OpenFile(targetFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open target format file: %v", err)
+	}
+	defer target.Close()
+
+	targetHeaderRow, targetDataStartRow, _, err := detectHeaderRow(targetFilePath, targetSheet, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect target header row: %v", err)
+	}
+	targetHeaders, err := target.readRowAt(targetSheet, targetHeaderRow)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read target headers: %v", err)
+	}
+
+	// sourceCol[i] is the 0-based scanned-column index that feeds output
+	// column i, or -1 when no mapped scanned column landed on that target
+	// header.
+	sourceCol := make([]int, len(targetHeaders))
+	for i, targetHeader := range targetHeaders {
+		sourceCol[i] = -1
+		scanned, ok := targetToScanned[strings.TrimSpace(targetHeader)]
+		if !ok {
+			continue
+		}
+		if idx, ok := scannedIndex[scanned]; ok {
+			sourceCol[i] = idx
+		}
+	}
+
+	outputPath := sinkOutputPath(mappingFilePath, inputFilePath, sink)
+	if tableCount > 1 {
+		// Several tables share this sheet; give each its own output file
+		// instead of the last one silently overwriting the rest.
+		outputPath = suffixForTable(outputPath, targetTableName)
+	}
+
+	var rowsWritten int
+	if sink == SinkXLSX {
+		rowsWritten, err = writeXLSXOutput(target, targetSheet, targetHeaderRow, targetDataStartRow, targetHeaders, input, dataStartRow, tableEndTolerance, cleanFormulaOnlyRows, sourceCol, outputPath, styleMode)
+	} else {
+		rowsWritten, err = writeTabularOutput(outputPath, sink, targetSheet, targetHeaders, input, dataStartRow, tableEndTolerance, cleanFormulaOnlyRows, sourceCol)
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.RowsWritten = rowsWritten
+
+	logger.Info("Native file format completed", "input", inputFilePath, "output", outputPath, "rows_written", rowsWritten)
+	return result, nil
+}
+
+// sinkOutputPath places the formatted output next to mappingFilePath (the
+// scan output directory) under inputFilePath's own base name, substituting
+// sink's extension so a .xlsx input formatted to a CSV sink still produces a
+// "<name>.csv" file.
+func sinkOutputPath(mappingFilePath, inputFilePath string, sink SinkKind) string {
+	base := filepath.Base(inputFilePath)
+	base = strings.TrimSuffix(base, filepath.Ext(base)) + sink.ext()
+	return filepath.Join(filepath.Dir(mappingFilePath), base)
+}
+
+// writeXLSXOutput builds the reordered output as a new workbook and saves it
+// to outputPath. styleMode controls how much of target's visual formatting
+// rides along with the cell values: StyleModeNone copies neither
+// header nor data-row style; StyleModeHeaderOnly additionally copies the
+// header row's style; StyleModeFull copies every row's style plus the
+// template sheet's merged cells, column/row geometry, frozen panes, and
+// print area. It returns the number of data rows written.
+func writeXLSXOutput(target *Editor, targetSheet string, targetHeaderRow, targetDataStartRow int, targetHeaders []string, input *Editor, dataStartRow, tableEndTolerance int, cleanFormulaOnlyRows bool, sourceCol []int, outputPath string, styleMode StyleMode) (int, error) {
+	output := CreateNewFile()
+	if targetSheet != "Sheet1" {
+		if _, err := output.file.NewSheet(targetSheet); err != nil {
+			return 0, fmt.Errorf("failed to create output sheet: %v", err)
+		}
+		if err := output.file.DeleteSheet("Sheet1"); err != nil {
+			return 0, fmt.Errorf("failed to drop default output sheet: %v", err)
+		}
+	}
+
+	styleCache := make(map[int]int)
+	if styleMode != StyleModeNone {
+		if err := writeStyledRow(target, targetSheet, targetHeaderRow, output, targetSheet, 1, len(targetHeaders), styleCache); err != nil {
+			return 0, fmt.Errorf("failed to copy header style: %v", err)
+		}
+	}
+	for i, header := range targetHeaders {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		if err := output.SetCellValue(targetSheet, cell, header); err != nil {
+			return 0, fmt.Errorf("failed to write header %s: %v", cell, err)
+		}
+	}
+
+	outRow := 1 // row 1 is the header
+	rowsWritten, err := streamMappedRows(input, targetSheet, dataStartRow, tableEndTolerance, cleanFormulaOnlyRows, sourceCol, func(values []string) error {
+		outRow++
+		if styleMode == StyleModeFull {
+			if err := writeStyledRow(target, targetSheet, targetDataStartRow, output, targetSheet, outRow, len(sourceCol), styleCache); err != nil {
+				return fmt.Errorf("failed to copy data row style: %v", err)
+			}
+		}
+		for destIdx, value := range values {
+			if value == "" {
+				continue
+			}
+			cell, err := excelize.CoordinatesToCellName(destIdx+1, outRow)
+			if err != nil {
+				return err
+			}
+			if err := output.SetCellValue(targetSheet, cell, value); err != nil {
+				return fmt.Errorf("failed to write cell %s: %v", cell, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to write data rows: %v", err)
+	}
+
+	if styleMode == StyleModeFull {
+		if err := copySheetGeometry(target, output, targetSheet, targetSheet, outRow, len(targetHeaders)); err != nil {
+			return 0, fmt.Errorf("failed to copy sheet geometry: %v", err)
+		}
+	}
+
+	if err := output.SaveAs(outputPath); err != nil {
+		return 0, fmt.Errorf("failed to save formatted file: %v", err)
+	}
+	return rowsWritten, nil
+}
+
+// writeTabularOutput writes the reordered output as a CSV/TSV file via the
+// tabular package. CSV/TSV carries no cell styling, so unlike
+// writeXLSXOutput there's nothing to copy from target beyond its header
+// order.
+func writeTabularOutput(outputPath string, sink SinkKind, targetSheet string, targetHeaders []string, input *Editor, dataStartRow, tableEndTolerance int, cleanFormulaOnlyRows bool, sourceCol []int) (int, error) {
+	writer, err := tabular.NewWriter(outputPath, tabular.Format(sink), targetSheet)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create output file: %v", err)
+	}
+	defer writer.Close()
+
+	if err := writer.WriteHeader(targetHeaders); err != nil {
+		return 0, fmt.Errorf("failed to write header: %v", err)
+	}
+
+	rowsWritten, err := streamMappedRows(input, targetSheet, dataStartRow, tableEndTolerance, cleanFormulaOnlyRows, sourceCol, writer.WriteRow)
+	if err != nil {
+		return 0, fmt.Errorf("failed to write data rows: %v", err)
+	}
+	return rowsWritten, nil
+}
+
+// mappingFile is the on-disk shape of the column-mapping JSON that
+// mapping.MappingConfig also reads and writes. FormatFileNative decodes it
+// independently here rather than importing the mapping package: mapping
+// imports excel (SuggestMappings uses CleanColumnName), so an excel ->
+// mapping import would be a cycle.
+type mappingFile struct {
+	Mappings []mappingFileEntry `json:"mappings"`
+}
+
+type mappingFileEntry struct {
+	ScannedColumn string `json:"scanned_column"`
+	TargetColumn  string `json:"target_column"`
+	IsIgnored     bool   `json:"is_ignored"`
+}
+
+// loadMapping reads and parses a column-mapping JSON file through fsys.
+func loadMapping(fsys FS, path string) (*mappingFile, error) {
+	file, err := fsys.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open mapping file: %v", err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mapping file: %v", err)
+	}
+
+	var mc mappingFile
+	if err := json.Unmarshal(data, &mc); err != nil {
+		return nil, fmt.Errorf("failed to parse mapping file: %v", err)
+	}
+	return &mc, nil
+}
+
+// invertMapping turns a mappingFile into target-column -> scanned-column,
+// skipping ignored entries and logging a warning each time a scanned column
+// claims a target an earlier entry already claimed (the earlier mapping
+// wins).
+func invertMapping(mc *mappingFile) map[string]string {
+	targetToScanned := make(map[string]string, len(mc.Mappings))
+	for _, m := range mc.Mappings {
+		if m.IsIgnored || m.TargetColumn == "" {
+			continue
+		}
+		if existing, ok := targetToScanned[m.TargetColumn]; ok {
+			logger.Warn("Multiple scanned columns map to the same target column, keeping the first",
+				"target_column", m.TargetColumn, "kept", existing, "dropped", m.ScannedColumn)
+			continue
+		}
+		targetToScanned[m.TargetColumn] = m.ScannedColumn
+	}
+	return targetToScanned
+}
+
+// writeStyledRow copies the cell style of row (on src/srcSheet) across the
+// first numCols columns onto destRow (on dest/destSheet), memoizing
+// source-style-ID -> destination-style-ID in styleCache.
+func writeStyledRow(src *Editor, srcSheet string, row int, dest *Editor, destSheet string, destRow, numCols int, styleCache map[int]int) error {
+	for col := 1; col <= numCols; col++ {
+		cell, err := excelize.CoordinatesToCellName(col, row)
+		if err != nil {
+			return err
+		}
+		destCell, err := excelize.CoordinatesToCellName(col, destRow)
+		if err != nil {
+			return err
+		}
+		if err := copyNativeCellStyle(src, dest, srcSheet, destSheet, cell, destCell, styleCache); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyNativeCellStyle duplicates the style of src's srcCell onto dest's
+// destCell, reusing styleCache across calls so repeated styles don't blow
+// up the destination workbook's style table.
+func copyNativeCellStyle(src, dest *Editor, srcSheet, destSheet, srcCell, destCell string, styleCache map[int]int) error {
+	srcStyleID, err := src.file.GetCellStyle(srcSheet, srcCell)
+	if err != nil {
+		return fmt.Errorf("failed to read style for %s: %v", srcCell, err)
+	}
+	if srcStyleID == 0 {
+		return nil // default style, nothing to copy
+	}
+
+	destStyleID, ok := styleCache[srcStyleID]
+	if !ok {
+		style, err := src.file.GetStyle(srcStyleID)
+		if err != nil {
+			return fmt.Errorf("failed to read style definition %d: %v", srcStyleID, err)
+		}
+		destStyleID, err = dest.file.NewStyle(style)
+		if err != nil {
+			return fmt.Errorf("failed to create style for %s: %v", destCell, err)
+		}
+		styleCache[srcStyleID] = destStyleID
+	}
+
+	return dest.file.SetCellStyle(destSheet, destCell, destCell, destStyleID)
+}
+
+// streamMappedRows streams input's data rows starting at dataStartRow,
+// honoring the tableEndTolerance heuristic (stop once more than
+// tableEndTolerance consecutive rows are entirely blank) and optionally
+// dropping rows made up only of formulas, reordering each kept row into
+// target column order per sourceCol and invoking emit with the result. It
+// returns the number of rows actually emitted; the sink-specific write
+// (styled XLSX cells vs. a plain CSV/TSV record) lives in emit.
+//
+// cleanFormulaOnlyRows can't actually be honored here: telling a formula
+// cell from a literal one needs excelize.GetCellFormula, and calling that
+// even once forces excelize to parse and cache the sheet's entire XML in
+// memory, which is the exact materialize-the-whole-sheet cost StreamRows
+// exists to avoid. So this logs a one-time warning and keeps every
+// non-blank row instead of silently falling back to full materialization
+// on the common (default-on) path multi-hundred-MB inputs depend on
+// staying streamed.
+func streamMappedRows(input *Editor, sheet string, dataStartRow, tableEndTolerance int, cleanFormulaOnlyRows bool, sourceCol []int, emit func(values []string) error) (int, error) {
+	it, err := input.StreamRows(sheet)
+	if err != nil {
+		return 0, err
+	}
+	defer it.Close()
+
+	if cleanFormulaOnlyRows {
+		logger.Warn("clean_formula_only_rows has no effect on the streaming native formatter: distinguishing formula cells from literal ones would force excelize to materialize the whole sheet, defeating streaming",
+			"sheet", sheet)
+	}
+
+	rowIdx := 0
+	blankStreak := 0
+	rowsWritten := 0
+
+	for it.Next() {
+		rowIdx++
+		row, err := it.Columns()
+		if err != nil {
+			return 0, fmt.Errorf("failed to read row %d: %v", rowIdx, err)
+		}
+		if rowIdx < dataStartRow {
+			continue
+		}
+
+		if isBlankRow(row) {
+			blankStreak++
+			if blankStreak > tableEndTolerance {
+				break
+			}
+			continue
+		}
+		blankStreak = 0
+
+		values := make([]string, len(sourceCol))
+		for destIdx, srcIdx := range sourceCol {
+			if srcIdx < 0 || srcIdx >= len(row) {
+				continue
+			}
+			values[destIdx] = row[srcIdx]
+		}
+		if err := emit(values); err != nil {
+			return 0, err
+		}
+		rowsWritten++
+	}
+	if err := it.Err(); err != nil {
+		return 0, fmt.Errorf("failed to stream input rows: %v", err)
+	}
+
+	return rowsWritten, nil
+}
+
+// isBlankRow reports whether every cell in row is empty once trimmed.
+func isBlankRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,23 @@
+package excel
+
+import "sheetFmt/internal/fs"
+
+// FS abstracts the filesystem operations ScanAllColumnsInDirectoryFS and
+// FormatFileNative's input validation need, so callers can substitute an
+// in-memory filesystem in tests instead of touching a real disk. It's a
+// re-export of internal/fs.FS rather than a second definition, so excel and
+// mapping share one afero-style implementation.
+type FS = fs.FS
+
+// File is the subset of *os.File an FS implementation reads and writes
+// plain-text output through.
+type File = fs.File
+
+// OSFS is the default FS, backed directly by the os and filepath packages.
+type OSFS = fs.OSFS
+
+// MemFS is an in-memory FS for tests.
+type MemFS = fs.MemFS
+
+// NewMemFS returns an empty MemFS.
+func NewMemFS() *MemFS { return fs.NewMemFS() }
@@ -0,0 +1,120 @@
+package excel
+
+import (
+	"path/filepath"
+	"strings"
+
+	"sheetFmt/internal/tabular"
+)
+
+// SourceKind identifies the on-disk format FormatFileNative reads its input
+// from. It mirrors tabular.Format rather than redefining the same three
+// values, so the two packages never drift.
+type SourceKind string
+
+// SinkKind identifies the on-disk format FormatFileNative writes its output
+// as.
+type SinkKind string
+
+const (
+	SourceXLSX SourceKind = SourceKind(tabular.FormatXLSX)
+	SourceCSV  SourceKind = SourceKind(tabular.FormatCSV)
+	SourceTSV  SourceKind = SourceKind(tabular.FormatTSV)
+
+	SinkXLSX SinkKind = SinkKind(tabular.FormatXLSX)
+	SinkCSV  SinkKind = SinkKind(tabular.FormatCSV)
+	SinkTSV  SinkKind = SinkKind(tabular.FormatTSV)
+)
+
+// detectSourceKind maps path's extension to a SourceKind via tabular's
+// format detection, so callers get the same "unsupported extension" error
+// message as the rest of the tabular plumbing.
+func detectSourceKind(path string) (SourceKind, error) {
+	format, err := tabular.DetectFormat(path)
+	if err != nil {
+		return "", err
+	}
+	return SourceKind(format), nil
+}
+
+// detectSinkKind maps path's extension to a SinkKind via tabular's format
+// detection.
+func detectSinkKind(path string) (SinkKind, error) {
+	format, err := tabular.DetectFormat(path)
+	if err != nil {
+		return "", err
+	}
+	return SinkKind(format), nil
+}
+
+// resolveSinkKind returns explicit when the caller forced one, otherwise
+// infers the sink from targetFilePath's extension, falling back to
+// inputFilePath's, and defaulting to XLSX when neither names a CSV/TSV
+// file. This lets "the input or target is a .csv file" keep working for
+// callers that don't care to be explicit.
+func resolveSinkKind(inputFilePath, targetFilePath string, explicit SinkKind) SinkKind {
+	if explicit != "" {
+		return explicit
+	}
+	if kind, err := detectSinkKind(targetFilePath); err == nil && kind != SinkXLSX {
+		return kind
+	}
+	if kind, err := detectSinkKind(inputFilePath); err == nil && kind != SinkXLSX {
+		return kind
+	}
+	return SinkXLSX
+}
+
+// OutputPathFor returns the path FormatFile will write its result to for the
+// given outputFormat ("xlsx", "csv", "both", or "" to infer from extensions),
+// so callers building a manifest don't need to reimplement FormatFile's
+// output-path derivation themselves. For "both" it returns the .xlsx path,
+// since that's the one FormatFile writes first.
+func OutputPathFor(inputFilePath, targetFilePath, mappingFilePath, outputFormat string) string {
+	var sink SinkKind
+	switch outputFormat {
+	case "csv":
+		sink = SinkCSV
+	case "xlsx", "both":
+		sink = SinkXLSX
+	}
+	sink = resolveSinkKind(inputFilePath, targetFilePath, sink)
+	return sinkOutputPath(mappingFilePath, inputFilePath, sink)
+}
+
+// OutputPathForTable is OutputPathFor, but gives tableName its own output
+// file (a "-<tableName>" suffix before the extension) when multiTable is
+// true. format-all passes multiTable whenever the sheet declares more than
+// one table, matching the suffix FormatFileNative gives those outputs so
+// formatting every table on a sheet doesn't have each one overwrite the
+// last; a sheet with at most one table keeps the plain OutputPathFor path.
+func OutputPathForTable(inputFilePath, targetFilePath, mappingFilePath, outputFormat, tableName string, multiTable bool) string {
+	path := OutputPathFor(inputFilePath, targetFilePath, mappingFilePath, outputFormat)
+	if !multiTable {
+		return path
+	}
+	return suffixForTable(path, tableName)
+}
+
+// suffixForTable appends "-<tableName>" before path's extension, or returns
+// path unchanged when tableName is empty.
+func suffixForTable(path, tableName string) string {
+	if tableName == "" {
+		return path
+	}
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "-" + tableName + ext
+}
+
+// ext returns the file extension (including the leading dot) FormatFileNative
+// should use for an output file written in kind.
+func (k SinkKind) ext() string {
+	switch k {
+	case SinkCSV:
+		return ".csv"
+	case SinkTSV:
+		return ".tsv"
+	default:
+		return ".xlsx"
+	}
+}
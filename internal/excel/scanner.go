@@ -7,12 +7,13 @@ import (
 	"path/filepath"
 	"regexp"
 	"sheetFmt/internal/logger"
+	"sheetFmt/internal/tabular"
 	"sort"
 	"strings"
 )
 
-// cleanColumnName cleans column names by removing HTML tags, extra whitespace, and taking first line
-func cleanColumnName(rawName string) string {
+// CleanColumnName cleans column names by removing HTML tags, extra whitespace, and taking first line
+func CleanColumnName(rawName string) string {
 	if rawName == "" {
 		return ""
 	}
@@ -48,38 +49,105 @@ func cleanColumnName(rawName string) string {
 	return cleaned
 }
 
-// ScanAllColumnsInDirectory scans all .xlsx files in the specified directory
-// and extracts all unique column names from all sheets, saving them to scanned_columns file
+// mergeHeaders cleans each header and merges it into uniqueColumns, tracking
+// the original value and first-seen file for debugging. It returns the
+// number of headers merged and the number found empty (before or after
+// cleaning).
+func mergeHeaders(headers []string, fileName string, uniqueColumns map[string]bool, cleaningStats map[string]string, headerSources map[string]string) (found, empty int) {
+	for _, header := range headers {
+		rawHeader := strings.TrimSpace(header)
+		if rawHeader == "" {
+			empty++
+			continue
+		}
+
+		cleanHeader := CleanColumnName(rawHeader)
+		if cleanHeader == "" {
+			empty++
+			continue
+		}
+
+		if !uniqueColumns[cleanHeader] {
+			headerSources[cleanHeader] = fileName
+		}
+		uniqueColumns[cleanHeader] = true
+		found++
+
+		if cleanHeader != rawHeader {
+			if _, exists := cleaningStats[cleanHeader]; !exists {
+				cleaningStats[cleanHeader] = rawHeader
+			}
+		}
+	}
+	return found, empty
+}
+
+// ScanOptions configures ScanAllColumnsInDirectory's per-file scan
+// strategy.
+type ScanOptions struct {
+	// Stream forces every file through the streaming scan path
+	// (StreamColumnHeaders) instead of the GetColumnHeaders heuristic,
+	// regardless of size. ScanAllColumnsInDirectoryFS switches a file to
+	// streaming on its own once it crosses largeFileThresholdBytes, so this
+	// is only needed to force streaming on smaller files too.
+	Stream bool
+	// HeaderRowDepth is passed through to StreamColumnHeaders when the
+	// streaming path is used. Zero defaults to 1 there.
+	HeaderRowDepth int
+}
+
+// ScanAllColumnsInDirectory scans all .xlsx/.csv/.tsv files in the specified
+// directory and extracts all unique column names from all sheets, saving
+// them to scanned_columns file. It's a thin shim over
+// ScanAllColumnsInDirectoryFS using the real filesystem and default options.
 func ScanAllColumnsInDirectory(inputDir, outputDir string) error {
+	return ScanAllColumnsInDirectoryFS(OSFS{}, inputDir, outputDir, ScanOptions{})
+}
+
+// ScanAllColumnsInDirectoryWithOptions is ScanAllColumnsInDirectory with an
+// explicit ScanOptions, for callers (e.g. a --stream CLI flag) that want to
+// control the per-file scan strategy. It's a thin shim over
+// ScanAllColumnsInDirectoryFS using the real filesystem.
+func ScanAllColumnsInDirectoryWithOptions(inputDir, outputDir string, opts ScanOptions) error {
+	return ScanAllColumnsInDirectoryFS(OSFS{}, inputDir, outputDir, opts)
+}
+
+// ScanAllColumnsInDirectoryFS is ScanAllColumnsInDirectory with the
+// directory walk and scanned_columns output routed through fsys instead of
+// the os package directly, so tests can pass a MemFS. The per-file column
+// scan itself (scanFileColumns/scanFileColumnsStreaming) still reads
+// workbooks straight off disk via excelize/tabular, which don't accept a
+// pluggable filesystem.
+func ScanAllColumnsInDirectoryFS(fsys FS, inputDir, outputDir string, opts ScanOptions) error {
 	logger.Info("Starting directory scan", "input_dir", inputDir, "output_dir", outputDir)
 
 	// Create the input directory if it doesn't exist
-	if err := os.MkdirAll(inputDir, 0755); err != nil {
+	if err := fsys.MkdirAll(inputDir, 0755); err != nil {
 		logger.Error("Failed to create input directory", "directory", inputDir, "error", err)
 		return fmt.Errorf("failed to create input directory: %v", err)
 	}
 
 	// Create the output directory if it doesn't exist
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
+	if err := fsys.MkdirAll(outputDir, 0755); err != nil {
 		logger.Error("Failed to create output directory", "directory", outputDir, "error", err)
 		return fmt.Errorf("failed to create output directory: %v", err)
 	}
 
-	// Get all .xlsx files in the directory
-	xlsxFiles, err := getXlsxFiles(inputDir)
+	// Get all .xlsx/.csv/.tsv files in the directory
+	tabularFiles, err := getTabularFilesFS(fsys, inputDir)
 	if err != nil {
-		logger.Error("Failed to get xlsx files from directory", "directory", inputDir, "error", err)
-		return fmt.Errorf("failed to get xlsx files: %v", err)
+		logger.Error("Failed to get tabular files from directory", "directory", inputDir, "error", err)
+		return fmt.Errorf("failed to get tabular files: %v", err)
 	}
 
-	if len(xlsxFiles) == 0 {
-		logger.Warn("No .xlsx files found in directory", "directory", inputDir)
-		fmt.Printf("No .xlsx files found in directory: %s\n", inputDir)
+	if len(tabularFiles) == 0 {
+		logger.Warn("No tabular files found in directory", "directory", inputDir)
+		fmt.Printf("No .xlsx/.csv/.tsv files found in directory: %s\n", inputDir)
 		return nil
 	}
 
-	logger.Info("Excel files discovered", "file_count", len(xlsxFiles), "directory", inputDir)
-	fmt.Printf("Found %d Excel files to scan\n", len(xlsxFiles))
+	logger.Info("Tabular files discovered", "file_count", len(tabularFiles), "directory", inputDir)
+	fmt.Printf("Found %d tabular files to scan\n", len(tabularFiles))
 
 	// Set to store unique column names
 	uniqueColumns := make(map[string]bool)
@@ -100,15 +168,30 @@ func ScanAllColumnsInDirectory(inputDir, outputDir string) error {
 	)
 
 	// Process each Excel file
-	for i, filePath := range xlsxFiles {
+	for i, filePath := range tabularFiles {
 		fileName := filepath.Base(filePath)
 		logger.Info("Processing file",
 			"file", fileName,
-			"progress", fmt.Sprintf("%d/%d", i+1, len(xlsxFiles)),
+			"progress", fmt.Sprintf("%d/%d", i+1, len(tabularFiles)),
 			"path", filePath)
-		fmt.Printf("[%d/%d] Scanning: %s\n", i+1, len(xlsxFiles), fileName)
+		fmt.Printf("[%d/%d] Scanning: %s\n", i+1, len(tabularFiles), fileName)
+
+		useStream := opts.Stream
+		if !useStream {
+			if info, statErr := fsys.Stat(filePath); statErr == nil && info.Size() > largeFileThresholdBytes {
+				useStream = true
+				logger.Info("File exceeds size threshold, switching to streaming scan",
+					"file", fileName, "size_mb", info.Size()/(1024*1024))
+			}
+		}
 
-		fileStats, err := scanFileColumns(filePath, uniqueColumns, cleaningStats, headerSources)
+		var fileStats FileStats
+		var err error
+		if useStream {
+			fileStats, err = scanFileColumnsStreaming(filePath, opts.HeaderRowDepth, uniqueColumns, cleaningStats, headerSources)
+		} else {
+			fileStats, err = scanFileColumns(filePath, uniqueColumns, cleaningStats, headerSources)
+		}
 		if err != nil {
 			logger.Error("Failed to scan file completely",
 				"file", fileName,
@@ -165,7 +248,7 @@ func ScanAllColumnsInDirectory(inputDir, outputDir string) error {
 
 	// Log comprehensive scanning statistics
 	logger.Info("Scan statistics",
-		"total_files_found", len(xlsxFiles),
+		"total_files_found", len(tabularFiles),
 		"total_files_processed", totalFilesProcessed,
 		"total_files_with_errors", totalFilesWithErrors,
 		"total_sheets_processed", totalSheetsProcessed,
@@ -179,7 +262,7 @@ func ScanAllColumnsInDirectory(inputDir, outputDir string) error {
 	outputFilePath := filepath.Join(outputDir, "scanned_columns")
 	logger.Info("Writing scanned columns to file", "output_file", outputFilePath, "column_count", len(columnNames))
 
-	err = writeColumnsToFile(outputFilePath, columnNames)
+	err = writeColumnsToFileFS(fsys, outputFilePath, columnNames)
 	if err != nil {
 		logger.Error("Failed to write columns to file", "output_file", outputFilePath, "error", err)
 		return fmt.Errorf("failed to write columns to file: %v", err)
@@ -191,7 +274,7 @@ func ScanAllColumnsInDirectory(inputDir, outputDir string) error {
 
 	// Print final summary
 	fmt.Printf("\nScan Summary:\n")
-	fmt.Printf("   Files processed: %d/%d\n", totalFilesProcessed-totalFilesWithErrors, len(xlsxFiles))
+	fmt.Printf("   Files processed: %d/%d\n", totalFilesProcessed-totalFilesWithErrors, len(tabularFiles))
 	fmt.Printf("   Sheets processed: %d\n", totalSheetsProcessed)
 	fmt.Printf("   Headers found: %d\n", totalHeadersFound)
 	fmt.Printf("   Unique columns: %d\n", len(columnNames))
@@ -217,8 +300,71 @@ type FileStats struct {
 	EmptyHeaders     int
 }
 
-// scanFileColumns scans all sheets in a single Excel file and adds column names to the set
+// scanFileColumns scans a single tabular file and adds its column names to
+// the shared set. XLSX files are scanned sheet by sheet; CSV/TSV files are
+// treated as a single implicit sheet whose first row is the header.
 func scanFileColumns(filePath string, uniqueColumns map[string]bool, cleaningStats map[string]string, headerSources map[string]string) (FileStats, error) {
+	format, err := tabular.DetectFormat(filePath)
+	if err != nil {
+		return FileStats{}, err
+	}
+
+	if format != tabular.FormatXLSX {
+		return scanCSVFileColumns(filePath, uniqueColumns, cleaningStats, headerSources)
+	}
+	return scanXLSXFileColumns(filePath, uniqueColumns, cleaningStats, headerSources)
+}
+
+// scanFileColumnsStreaming is scanFileColumns's streaming-path analog: it
+// reads only the first few rows of each sheet via StreamColumnHeaders
+// instead of the GetColumnHeaders heuristic, which scans a sheet end to end
+// looking for the rightmost populated column. Used for files too large to
+// scan the heuristic way cheaply.
+func scanFileColumnsStreaming(filePath string, headerRowDepth int, uniqueColumns map[string]bool, cleaningStats map[string]string, headerSources map[string]string) (FileStats, error) {
+	stats := FileStats{}
+	fileName := filepath.Base(filePath)
+
+	err := StreamColumnHeaders(filePath, StreamOptions{HeaderRowDepth: headerRowDepth}, func(sheet string, headers []string) error {
+		found, empty := mergeHeaders(headers, fileName, uniqueColumns, cleaningStats, headerSources)
+		stats.SheetsProcessed++
+		stats.HeadersFound += found
+		stats.EmptyHeaders += empty
+		return nil
+	})
+	if err != nil {
+		return stats, fmt.Errorf("failed to stream file: %v", err)
+	}
+
+	return stats, nil
+}
+
+// scanCSVFileColumns reads the header row of a CSV/TSV file through the
+// tabular package and merges it into the shared column set.
+func scanCSVFileColumns(filePath string, uniqueColumns map[string]bool, cleaningStats map[string]string, headerSources map[string]string) (FileStats, error) {
+	stats := FileStats{}
+	fileName := filepath.Base(filePath)
+
+	reader, err := tabular.OpenReader(filePath, "")
+	if err != nil {
+		return stats, fmt.Errorf("failed to open file: %v", err)
+	}
+	defer reader.Close()
+
+	headers, err := reader.Headers()
+	if err != nil {
+		return stats, fmt.Errorf("failed to read headers: %v", err)
+	}
+
+	found, empty := mergeHeaders(headers, fileName, uniqueColumns, cleaningStats, headerSources)
+	stats.SheetsProcessed = 1
+	stats.HeadersFound = found
+	stats.EmptyHeaders = empty
+	return stats, nil
+}
+
+// scanXLSXFileColumns scans all sheets in a single Excel file and adds
+// column names to the set.
+func scanXLSXFileColumns(filePath string, uniqueColumns map[string]bool, cleaningStats map[string]string, headerSources map[string]string) (FileStats, error) {
 	stats := FileStats{}
 	fileName := filepath.Base(filePath)
 
@@ -273,55 +419,13 @@ func scanFileColumns(filePath string, uniqueColumns map[string]bool, cleaningSta
 			continue
 		}
 
-		// Track sheet-level statistics
-		sheetHeadersFound := 0
-		sheetEmptyHeaders := 0
-		sheetCleanedHeaders := 0
-
 		// Add each header to the unique set after cleaning
-		for headerIndex, header := range headers {
-			rawHeader := strings.TrimSpace(header)
-
-			if rawHeader == "" {
-				sheetEmptyHeaders++
-				logger.Debug("Found empty header",
-					"sheet", sheetName,
-					"file", fileName,
-					"header_index", headerIndex)
-				continue
-			}
-
-			// Clean the header name
-			cleanHeader := cleanColumnName(rawHeader)
-			if cleanHeader != "" {
-				// Track first occurrence of this header
-				if !uniqueColumns[cleanHeader] {
-					headerSources[cleanHeader] = fileName
-				}
-
-				uniqueColumns[cleanHeader] = true
-				sheetHeadersFound++
-
-				// Track cleaning statistics
-				if cleanHeader != rawHeader {
-					sheetCleanedHeaders++
-					// Track cleaning for debugging (only store first occurrence)
-					if _, exists := cleaningStats[cleanHeader]; !exists {
-						cleaningStats[cleanHeader] = rawHeader
-					}
-					logger.Debug("Header cleaned",
-						"sheet", sheetName,
-						"file", fileName,
-						"original", rawHeader,
-						"cleaned", cleanHeader)
-				}
-			} else {
-				logger.Debug("Header became empty after cleaning",
-					"sheet", sheetName,
-					"file", fileName,
-					"header_index", headerIndex,
-					"original", rawHeader)
-				sheetEmptyHeaders++
+		sheetHeadersFound, sheetEmptyHeaders := mergeHeaders(headers, fileName, uniqueColumns, cleaningStats, headerSources)
+		sheetCleanedHeaders := 0
+		for _, header := range headers {
+			raw := strings.TrimSpace(header)
+			if clean := CleanColumnName(raw); clean != "" && clean != raw {
+				sheetCleanedHeaders++
 			}
 		}
 
@@ -358,19 +462,31 @@ func scanFileColumns(filePath string, uniqueColumns map[string]bool, cleaningSta
 	return stats, nil
 }
 
-// getXlsxFiles returns all .xlsx files in the specified directory
-func getXlsxFiles(dir string) ([]string, error) {
-	logger.Debug("Scanning directory for Excel files", "directory", dir)
+// getTabularFiles returns all .xlsx, .csv, and .tsv files in the specified
+// directory. It's a thin shim over getTabularFilesFS using the real
+// filesystem.
+func getTabularFiles(dir string) ([]string, error) {
+	return getTabularFilesFS(OSFS{}, dir)
+}
+
+// getTabularFilesFS is getTabularFiles with the directory walk routed
+// through fsys instead of filepath.Walk directly.
+func getTabularFilesFS(fsys FS, dir string) ([]string, error) {
+	logger.Debug("Scanning directory for tabular files", "directory", dir)
 
-	var xlsxFiles []string
+	var tabularFiles []string
 
-	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+	err := fsys.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			logger.Warn("Error accessing path during directory walk", "path", path, "error", err)
 			return err
 		}
 
-		if !info.IsDir() && strings.ToLower(filepath.Ext(path)) == ".xlsx" {
+		if !info.IsDir() {
+			if _, err := tabular.DetectFormat(path); err != nil {
+				return nil // not a tabular file we recognize
+			}
+
 			// Check for potential issues with file
 			if strings.HasPrefix(info.Name(), "~$") {
 				logger.Debug("Skipping temporary Excel file", "file", info.Name(), "path", path)
@@ -378,15 +494,15 @@ func getXlsxFiles(dir string) ([]string, error) {
 			}
 
 			if info.Size() == 0 {
-				logger.Warn("Found zero-size Excel file", "file", info.Name(), "path", path)
+				logger.Warn("Found zero-size tabular file", "file", info.Name(), "path", path)
 			}
 
-			if info.Size() > 100*1024*1024 { // 100MB
-				logger.Warn("Found very large Excel file", "file", info.Name(), "size_mb", info.Size()/(1024*1024), "path", path)
+			if info.Size() > largeFileThresholdBytes {
+				logger.Warn("Found very large tabular file", "file", info.Name(), "size_mb", info.Size()/(1024*1024), "path", path)
 			}
 
-			xlsxFiles = append(xlsxFiles, path)
-			logger.Debug("Found Excel file", "file", info.Name(), "size_bytes", info.Size(), "path", path)
+			tabularFiles = append(tabularFiles, path)
+			logger.Debug("Found tabular file", "file", info.Name(), "size_bytes", info.Size(), "path", path)
 		}
 
 		return nil
@@ -397,15 +513,22 @@ func getXlsxFiles(dir string) ([]string, error) {
 		return nil, err
 	}
 
-	logger.Info("Directory scan completed", "directory", dir, "xlsx_files_found", len(xlsxFiles))
-	return xlsxFiles, err
+	logger.Info("Directory scan completed", "directory", dir, "tabular_files_found", len(tabularFiles))
+	return tabularFiles, err
 }
 
-// writeColumnsToFile writes the column names to a plain text file
+// writeColumnsToFile writes the column names to a plain text file. It's a
+// thin shim over writeColumnsToFileFS using the real filesystem.
 func writeColumnsToFile(filename string, columns []string) error {
+	return writeColumnsToFileFS(OSFS{}, filename, columns)
+}
+
+// writeColumnsToFileFS is writeColumnsToFile with the write routed through
+// fsys instead of os.Create directly.
+func writeColumnsToFileFS(fsys FS, filename string, columns []string) error {
 	logger.Debug("Writing columns to file", "filename", filename, "column_count", len(columns))
 
-	file, err := os.Create(filename)
+	file, err := fsys.Create(filename)
 	if err != nil {
 		logger.Error("Failed to create output file", "filename", filename, "error", err)
 		return fmt.Errorf("failed to create file: %v", err)
@@ -0,0 +1,98 @@
+package excel
+
+import (
+	"fmt"
+
+	"sheetFmt/internal/tabular"
+)
+
+// largeFileThresholdBytes is the size past which ScanAllColumnsInDirectoryFS
+// switches a file to the streaming scan path automatically. It's the same
+// threshold getTabularFilesFS already uses to warn about very large files.
+const largeFileThresholdBytes = 100 * 1024 * 1024
+
+// StreamOptions configures StreamColumnHeaders.
+type StreamOptions struct {
+	// HeaderRowDepth is how many leading rows to read per sheet. Values
+	// above 1 concatenate columns across rows, useful when a workbook
+	// splits its header across more than one row. Zero defaults to 1.
+	HeaderRowDepth int
+}
+
+// StreamColumnHeaders opens filePath in row-streaming mode and calls emit
+// with the first opts.HeaderRowDepth rows of each sheet, then moves on
+// without reading the rest of the sheet. Unlike GetColumnHeaders, it never
+// scans a sheet end to end looking for the rightmost populated column, so
+// it stays cheap no matter how large the sheet is. CSV/TSV files are
+// treated as a single implicit sheet (sheet name "") whose header is just
+// its first row, via the tabular package.
+func StreamColumnHeaders(filePath string, opts StreamOptions, emit func(sheet string, headers []string) error) error {
+	depth := opts.HeaderRowDepth
+	if depth <= 0 {
+		depth = 1
+	}
+
+	format, err := tabular.DetectFormat(filePath)
+	if err != nil {
+		return err
+	}
+
+	if format != tabular.FormatXLSX {
+		reader, err := tabular.OpenReader(filePath, "")
+		if err != nil {
+			return fmt.Errorf("failed to open file: %v", err)
+		}
+		defer reader.Close()
+
+		headers, err := reader.Headers()
+		if err != nil {
+			return fmt.Errorf("failed to read headers: %v", err)
+		}
+		return emit("", headers)
+	}
+
+	editor, err := OpenFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %v", err)
+	}
+	defer editor.Close()
+
+	for _, sheet := range editor.GetSheetNames() {
+		headers, err := streamSheetHeaders(editor, sheet, depth)
+		if err != nil {
+			return err
+		}
+		if err := emit(sheet, headers); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// streamSheetHeaders reads the first depth rows of sheet and concatenates
+// their columns, closing the row iterator as soon as depth rows have been
+// read rather than streaming to the end of the sheet.
+func streamSheetHeaders(editor *Editor, sheet string, depth int) ([]string, error) {
+	it, err := editor.StreamRows(sheet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sheet %s: %v", sheet, err)
+	}
+	defer it.Close()
+
+	var headers []string
+	rowIdx := 0
+	for rowIdx < depth && it.Next() {
+		rowIdx++
+		row, err := it.Columns()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read row %d of sheet %s: %v", rowIdx, sheet, err)
+		}
+		headers = append(headers, row...)
+	}
+	if err := it.Err(); err != nil {
+		return nil, fmt.Errorf("failed to stream sheet %s: %v", sheet, err)
+	}
+
+	return headers, nil
+}
@@ -0,0 +1,80 @@
+package excel
+
+import (
+	"fmt"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// StyleMode controls how much of the template's visual formatting
+// FormatFileNative replays onto the output file, on top of the cell values
+// it always writes.
+type StyleMode string
+
+const (
+	// StyleModeNone writes cell values only: no header/data-row style, no
+	// geometry.
+	StyleModeNone StyleMode = "none"
+	// StyleModeHeaderOnly additionally copies the header row's style.
+	StyleModeHeaderOnly StyleMode = "header_only"
+	// StyleModeFull additionally copies every data row's style, plus
+	// merged cells, column widths, row heights, frozen panes, and the
+	// print area for the template sheet's full used range.
+	StyleModeFull StyleMode = "full"
+)
+
+// copySheetGeometry replays merged ranges, column widths, row heights,
+// frozen panes, and the print area from the target sheet onto the output
+// sheet. It's only invoked under StyleModeFull, after every row has been
+// written, so maxRows/maxCols cover the full used range.
+func copySheetGeometry(target, output *Editor, targetSheet, outputSheet string, maxRows, maxCols int) error {
+	merges, err := target.file.GetMergeCells(targetSheet)
+	if err != nil {
+		return fmt.Errorf("failed to read merged cells: %v", err)
+	}
+	for _, mc := range merges {
+		if err := output.file.MergeCell(outputSheet, mc.GetStartAxis(), mc.GetEndAxis()); err != nil {
+			return fmt.Errorf("failed to replay merge %s:%s: %v", mc.GetStartAxis(), mc.GetEndAxis(), err)
+		}
+	}
+
+	for colIndex := 0; colIndex < maxCols; colIndex++ {
+		colLetter, err := excelize.ColumnNumberToName(colIndex + 1)
+		if err != nil {
+			return err
+		}
+		width, err := target.file.GetColWidth(targetSheet, colLetter)
+		if err == nil && width > 0 {
+			if err := output.file.SetColWidth(outputSheet, colLetter, colLetter, width); err != nil {
+				return fmt.Errorf("failed to set column width for %s: %v", colLetter, err)
+			}
+		}
+	}
+
+	for row := 1; row <= maxRows; row++ {
+		height, err := target.file.GetRowHeight(targetSheet, row)
+		if err == nil && height > 0 {
+			if err := output.file.SetRowHeight(outputSheet, row, height); err != nil {
+				return fmt.Errorf("failed to set row height for row %d: %v", row, err)
+			}
+		}
+	}
+
+	if panes, err := target.file.GetPanes(targetSheet); err == nil {
+		if err := output.file.SetPanes(outputSheet, &panes); err != nil {
+			return fmt.Errorf("failed to replay frozen panes: %v", err)
+		}
+	}
+
+	for _, dn := range target.file.GetDefinedName() {
+		if dn.Name == "_xlnm.Print_Area" && dn.Scope == targetSheet {
+			replica := dn
+			replica.Scope = outputSheet
+			if err := output.file.SetDefinedName(&replica); err != nil {
+				return fmt.Errorf("failed to replay print area: %v", err)
+			}
+		}
+	}
+
+	return nil
+}
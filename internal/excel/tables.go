@@ -0,0 +1,100 @@
+package excel
+
+import (
+	"fmt"
+
+	"sheetFmt/internal/xlsxheader"
+)
+
+// DetectedTable describes a native Excel Table (ListObject) found on a
+// sheet, as opposed to a heuristically-guessed header row.
+type DetectedTable struct {
+	Sheet          string
+	Name           string
+	Range          string
+	Headers        []string
+	HeaderRow      int
+	HeaderRowCount int
+	DataStartRow   int
+}
+
+// DetectTables returns every native Excel Table defined on sheet. It reads
+// the workbook's table parts (xl/tables/table*.xml) through excelize's
+// table APIs rather than guessing where the data starts. The actual
+// detection lives in internal/xlsxheader so internal/tabular can share it
+// without importing this package back (see that package's doc comment).
+func (e *Editor) DetectTables(sheet string) ([]DetectedTable, error) {
+	tables, err := xlsxheader.DetectTables(e.file, e.filepath, sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	detected := make([]DetectedTable, len(tables))
+	for i, t := range tables {
+		detected[i] = DetectedTable{
+			Sheet:          t.Sheet,
+			Name:           t.Name,
+			Range:          t.Range,
+			Headers:        t.Headers,
+			HeaderRow:      t.HeaderRow,
+			HeaderRowCount: t.HeaderRowCount,
+			DataStartRow:   t.DataStartRow,
+		}
+	}
+	return detected, nil
+}
+
+// DetectTableNames returns the name of every native Excel Table declared on
+// sheet, or an empty slice when none are declared. format-all uses this to process
+// every table on a sheet instead of only the first one DetectTables finds.
+func (e *Editor) DetectTableNames(sheet string) ([]string, error) {
+	tables, err := e.DetectTables(sheet)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(tables))
+	for i, t := range tables {
+		names[i] = t.Name
+	}
+	return names, nil
+}
+
+// FindTable returns the named table on sheet, or false if no table with
+// that name is declared.
+func (e *Editor) FindTable(sheet, name string) (DetectedTable, bool, error) {
+	tables, err := e.DetectTables(sheet)
+	if err != nil {
+		return DetectedTable{}, false, err
+	}
+	for _, t := range tables {
+		if t.Name == name {
+			return t, true, nil
+		}
+	}
+	return DetectedTable{}, false, nil
+}
+
+// readRowAt streams sheet up to row (1-based) and returns its cell values.
+func (e *Editor) readRowAt(sheet string, row int) ([]string, error) {
+	it, err := e.StreamRows(sheet)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	current := 0
+	for it.Next() {
+		current++
+		cols, err := it.Columns()
+		if err != nil {
+			return nil, err
+		}
+		if current == row {
+			return cols, nil
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("row %d not found on sheet %s", row, sheet)
+}
@@ -0,0 +1,49 @@
+// Package fs provides a minimal afero-style filesystem abstraction so
+// directory-scanning and file-output code elsewhere in sheetFmt doesn't have
+// to hard-code os.Open/os.Create/filepath.Walk. Swapping in MemFS makes that
+// code unit-testable without touching a real disk, and a caller could
+// equally plug in a zip-backed or remote FS without changing scanner logic.
+package fs
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// File is the subset of *os.File that FS implementations need to support:
+// writing plain-text output (scanned_columns files, JSON mappings,
+// manifests) and reading it back. It does not cover the random-access
+// seeking excelize/tabular need to parse XLSX/CSV workbooks, which still
+// read straight off disk via their own os.Open calls regardless of which FS
+// the caller passed in.
+type File interface {
+	io.Reader
+	io.Writer
+	io.Closer
+}
+
+// FS abstracts the filesystem operations sheetFmt's directory scanning and
+// file output need: Open/Create for reading and writing plain files, Stat
+// for existence checks, MkdirAll for output directories, and Walk for
+// recursive directory scans.
+type FS interface {
+	Open(name string) (File, error)
+	Create(name string) (File, error)
+	Stat(name string) (os.FileInfo, error)
+	MkdirAll(path string, perm os.FileMode) error
+	Walk(root string, fn filepath.WalkFunc) error
+}
+
+// OSFS is the default FS, backed directly by the os and filepath packages.
+type OSFS struct{}
+
+func (OSFS) Open(name string) (File, error) { return os.Open(name) }
+
+func (OSFS) Create(name string) (File, error) { return os.Create(name) }
+
+func (OSFS) Stat(name string) (os.FileInfo, error) { return os.Stat(name) }
+
+func (OSFS) MkdirAll(path string, perm os.FileMode) error { return os.MkdirAll(path, perm) }
+
+func (OSFS) Walk(root string, fn filepath.WalkFunc) error { return filepath.Walk(root, fn) }
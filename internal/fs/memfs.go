@@ -0,0 +1,162 @@
+package fs
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemFS is an in-memory FS, primarily for tests: Create/Open operate on
+// byte slices keyed by path instead of touching a real disk.
+type MemFS struct {
+	mu    sync.Mutex
+	files map[string][]byte
+	dirs  map[string]bool
+}
+
+// NewMemFS returns an empty MemFS.
+func NewMemFS() *MemFS {
+	return &MemFS{
+		files: make(map[string][]byte),
+		dirs:  make(map[string]bool),
+	}
+}
+
+// Open returns a reader over name's stored content, or an error matching
+// os.IsNotExist if name has never been written.
+func (m *MemFS) Open(name string) (File, error) {
+	m.mu.Lock()
+	data, ok := m.files[name]
+	m.mu.Unlock()
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	return &memFile{name: name, reader: bytes.NewReader(data)}, nil
+}
+
+// Create returns a writer that replaces name's stored content with whatever
+// is written to it once Close is called.
+func (m *MemFS) Create(name string) (File, error) {
+	m.mu.Lock()
+	m.dirs[filepath.Dir(name)] = true
+	m.mu.Unlock()
+	return &memFile{name: name, fs: m, buf: &bytes.Buffer{}}, nil
+}
+
+// Stat reports whether name is a stored file or a directory created via
+// MkdirAll, returning an os.IsNotExist error otherwise.
+func (m *MemFS) Stat(name string) (os.FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if data, ok := m.files[name]; ok {
+		return memFileInfo{name: filepath.Base(name), size: int64(len(data))}, nil
+	}
+	if m.dirs[name] {
+		return memFileInfo{name: filepath.Base(name), isDir: true}, nil
+	}
+	return nil, &os.PathError{Op: "stat", Path: name, Err: os.ErrNotExist}
+}
+
+// MkdirAll records path as an existing directory; MemFS has no real
+// hierarchy, so it never errors.
+func (m *MemFS) MkdirAll(path string, perm os.FileMode) error {
+	m.mu.Lock()
+	m.dirs[path] = true
+	m.mu.Unlock()
+	return nil
+}
+
+// Walk visits every stored file under root in lexical order, mirroring
+// filepath.Walk closely enough for directory-scanning callers. Directories
+// themselves are not visited. fn returning filepath.SkipDir does not abort
+// the walk, but unlike filepath.Walk it skips nothing: the walk goes on to
+// the next file. Any other error from fn aborts the walk.
+func (m *MemFS) Walk(root string, fn filepath.WalkFunc) error {
+	m.mu.Lock()
+	var paths []string
+	for path := range m.files {
+		if underRoot(path, root) {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	infos := make([]os.FileInfo, len(paths))
+	for i, path := range paths {
+		infos[i] = memFileInfo{name: filepath.Base(path), size: int64(len(m.files[path]))}
+	}
+	m.mu.Unlock()
+
+	for i, path := range paths {
+		if err := fn(path, infos[i], nil); err != nil {
+			if err == filepath.SkipDir {
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// underRoot reports whether path lies at or under root, the way
+// filepath.Walk would report it as an entry of that walk.
+func underRoot(path, root string) bool {
+	root = filepath.Clean(root)
+	if root == "." {
+		return true
+	}
+	path = filepath.Clean(path)
+	return path == root || strings.HasPrefix(path, root+string(filepath.Separator))
+}
+
+// memFile implements File over either a read-only snapshot (from Open) or
+// an accumulating write buffer (from Create); it errors if used in the
+// direction it wasn't opened for, matching *os.File's permission-based
+// behavior closely enough for this package's callers.
+type memFile struct {
+	name   string
+	fs     *MemFS
+	reader *bytes.Reader
+	buf    *bytes.Buffer
+}
+
+func (f *memFile) Read(p []byte) (int, error) {
+	if f.reader == nil {
+		return 0, fmt.Errorf("%s: file not open for reading", f.name)
+	}
+	return f.reader.Read(p)
+}
+
+func (f *memFile) Write(p []byte) (int, error) {
+	if f.buf == nil {
+		return 0, fmt.Errorf("%s: file not open for writing", f.name)
+	}
+	return f.buf.Write(p)
+}
+
+func (f *memFile) Close() error {
+	if f.buf != nil {
+		f.fs.mu.Lock()
+		f.fs.files[f.name] = append([]byte(nil), f.buf.Bytes()...)
+		f.fs.mu.Unlock()
+	}
+	return nil
+}
+
+// memFileInfo is a minimal os.FileInfo for MemFS entries.
+type memFileInfo struct {
+	name  string
+	size  int64
+	isDir bool
+}
+
+func (fi memFileInfo) Name() string       { return fi.name }
+func (fi memFileInfo) Size() int64        { return fi.size }
+func (fi memFileInfo) Mode() os.FileMode  { return 0644 }
+func (fi memFileInfo) ModTime() time.Time { return time.Time{} }
+func (fi memFileInfo) IsDir() bool        { return fi.isDir }
+func (fi memFileInfo) Sys() interface{}   { return nil }
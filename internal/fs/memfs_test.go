@@ -0,0 +1,166 @@
+package fs
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMemFSCreateThenOpenRoundTrips(t *testing.T) {
+	m := NewMemFS()
+
+	w, err := m.Create("out/columns.txt")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := w.Write([]byte("hello")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	r, err := m.Open("out/columns.txt")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if string(data) != "hello" {
+		t.Errorf("got %q, want %q", data, "hello")
+	}
+}
+
+func TestMemFSOpenMissingFileReturnsNotExist(t *testing.T) {
+	m := NewMemFS()
+
+	_, err := m.Open("missing.txt")
+	if err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+	if !os.IsNotExist(err) {
+		t.Errorf("expected os.IsNotExist(err) to be true, got %v", err)
+	}
+}
+
+func TestMemFSStat(t *testing.T) {
+	m := NewMemFS()
+
+	if _, err := m.Stat("nope.txt"); !os.IsNotExist(err) {
+		t.Errorf("Stat on missing file: expected os.IsNotExist, got %v", err)
+	}
+
+	w, _ := m.Create("present.txt")
+	w.Write([]byte("abc"))
+	w.Close()
+
+	info, err := m.Stat("present.txt")
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if info.Size() != 3 {
+		t.Errorf("Size() = %d, want 3", info.Size())
+	}
+	if info.IsDir() {
+		t.Error("IsDir() = true, want false")
+	}
+
+	if err := m.MkdirAll("some/dir", 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	info, err = m.Stat("some/dir")
+	if err != nil {
+		t.Fatalf("Stat on dir: %v", err)
+	}
+	if !info.IsDir() {
+		t.Error("IsDir() = false, want true")
+	}
+}
+
+func TestMemFSWalkVisitsFilesUnderRootInOrder(t *testing.T) {
+	m := NewMemFS()
+	for _, name := range []string{"dir/b.csv", "dir/a.csv", "other/c.csv", "root.csv"} {
+		w, _ := m.Create(name)
+		w.Write([]byte("x"))
+		w.Close()
+	}
+
+	var visited []string
+	err := m.Walk("dir", func(path string, info os.FileInfo, err error) error {
+		visited = append(visited, path)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk: %v", err)
+	}
+
+	want := []string{"dir/a.csv", "dir/b.csv"}
+	if len(visited) != len(want) {
+		t.Fatalf("visited = %v, want %v", visited, want)
+	}
+	for i := range want {
+		if visited[i] != want[i] {
+			t.Errorf("visited[%d] = %q, want %q", i, visited[i], want[i])
+		}
+	}
+}
+
+func TestMemFSWalkSkipDirContinuesWithoutError(t *testing.T) {
+	m := NewMemFS()
+	for _, name := range []string{"a.csv", "b.csv"} {
+		w, _ := m.Create(name)
+		w.Close()
+	}
+
+	var visited []string
+	err := m.Walk(".", func(path string, info os.FileInfo, err error) error {
+		visited = append(visited, path)
+		return filepath.SkipDir
+	})
+	if err != nil {
+		t.Fatalf("Walk: %v", err)
+	}
+	if len(visited) != 2 {
+		t.Errorf("visited = %v, want both entries visited despite SkipDir", visited)
+	}
+}
+
+func TestMemFSWalkPropagatesOtherErrors(t *testing.T) {
+	m := NewMemFS()
+	w, _ := m.Create("a.csv")
+	w.Close()
+
+	boom := errors.New("boom")
+	err := m.Walk(".", func(path string, info os.FileInfo, err error) error {
+		return boom
+	})
+	if !errors.Is(err, boom) {
+		t.Errorf("Walk error = %v, want %v", err, boom)
+	}
+}
+
+func TestMemFileWriteOnlyFileRejectsRead(t *testing.T) {
+	m := NewMemFS()
+	w, _ := m.Create("write_only.txt")
+	if _, err := w.Read(make([]byte, 1)); err == nil {
+		t.Error("Read on a write-only memFile: expected an error, got nil")
+	}
+}
+
+func TestMemFileReadOnlyFileRejectsWrite(t *testing.T) {
+	m := NewMemFS()
+	w, _ := m.Create("seed.txt")
+	w.Write([]byte("seed"))
+	w.Close()
+
+	r, _ := m.Open("seed.txt")
+	if _, err := r.Write([]byte("x")); err == nil {
+		t.Error("Write on a read-only memFile: expected an error, got nil")
+	}
+}
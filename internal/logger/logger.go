@@ -1,27 +1,177 @@
 package logger
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// Logger is the process-wide structured logger. It starts out as a
+// minimal stderr logger so logging works before Init runs (e.g. if
+// config.LoadConfig itself fails); main calls Init as soon as a Config is
+// available to upgrade it to the configured, rotating file output.
+var Logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+// baseWriter, logFormat, logLevel, and logRedact back both Logger and
+// every Run's tee, so a Run can be built without reaching into Logger's
+// handler (slog.Handler exposes no way to recover the writer/options/
+// middleware it was built from).
+var (
+	baseWriter io.Writer = os.Stderr
+	logFormat            = "text"
+	logLevel             = slog.LevelInfo
+	logRedact  RedactConfig
 )
 
-var Logger *slog.Logger
+// Config controls Init's log level, output format, and rotation of
+// logs/sheetfmt.log. It mirrors config.AIConfig's style of a plain,
+// TOML-friendly struct; logger can't import the config package directly
+// since config already imports logger.
+type Config struct {
+	// Level is "debug", "info", "warn", or "error". Defaults to "info".
+	Level string
+	// Format is "text" or "json". Defaults to "text".
+	Format string
+	// MaxSizeMB is the size in megabytes a log file reaches before it's
+	// rotated. Defaults to 100.
+	MaxSizeMB int
+	// MaxBackups is how many rotated log files are kept. Defaults to 5.
+	MaxBackups int
+	// MaxAgeDays is how many days a rotated log file is kept. Defaults to 28.
+	MaxAgeDays int
+	// Redact configures the redaction middleware every handler this
+	// package builds is wrapped in.
+	Redact RedactConfig
+}
+
+// Init reconfigures the package logger per cfg: level, text or JSON
+// output, lumberjack-based size/age rotation of logs/sheetfmt.log, and
+// the redaction middleware every subsequent handler (including each
+// Run's) is built with. It replaces the old package init()'s hardcoded
+// construction, which panicked on open failure, so callers — including
+// tests — can supply their own Config and handle a returned error
+// instead.
+func Init(cfg Config) error {
+	if err := os.MkdirAll("logs", 0755); err != nil {
+		return fmt.Errorf("failed to create logs directory: %v", err)
+	}
+
+	logLevel = parseLevel(cfg.Level)
+	logFormat = strings.ToLower(cfg.Format)
+	logRedact = cfg.Redact
+
+	baseWriter = &lumberjack.Logger{
+		Filename:   filepath.Join("logs", "sheetfmt.log"),
+		MaxSize:    orDefault(cfg.MaxSizeMB, 100),
+		MaxBackups: orDefault(cfg.MaxBackups, 5),
+		MaxAge:     orDefault(cfg.MaxAgeDays, 28),
+	}
+
+	handler, err := buildHandler(baseWriter, logFormat, logLevel, logRedact)
+	if err != nil {
+		return fmt.Errorf("failed to configure log redaction: %v", err)
+	}
+	Logger = slog.New(handler)
+	return nil
+}
+
+func parseLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+func newHandler(w io.Writer, format string, level slog.Level) slog.Handler {
+	opts := &slog.HandlerOptions{Level: level}
+	if format == "json" {
+		return slog.NewJSONHandler(w, opts)
+	}
+	return slog.NewTextHandler(w, opts)
+}
+
+// buildHandler is newHandler wrapped in the redaction middleware, the
+// single place both Init and StartRun build a handler from so neither
+// can forget to apply it.
+func buildHandler(w io.Writer, format string, level slog.Level, redact RedactConfig) (slog.Handler, error) {
+	return newRedactingHandler(newHandler(w, format, level), redact)
+}
+
+func orDefault(v, def int) int {
+	if v <= 0 {
+		return def
+	}
+	return v
+}
+
+// Run is one invocation's logger: the package logger plus a correlation
+// ID attached to every entry via slog.With, tee'd to its own
+// logs/runs/<timestamp>-<corrID>.log so a user can zip and send one
+// run's logs without the rest of the history alongside it.
+type Run struct {
+	*slog.Logger
+	ID string
 
-func init() {
-	// Create logs directory
-	os.MkdirAll("logs", 0755)
-	
-	// Create log file
-	logFile, err := os.OpenFile(filepath.Join("logs", "sheetfmt.log"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	file *os.File
+}
+
+// StartRun begins a new correlated run against the package's current
+// base logger (as configured by the last Init call). Callers must defer
+// run.Close() to release its per-run file.
+func StartRun() (*Run, error) {
+	id, err := newCorrelationID()
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("failed to generate correlation ID: %v", err)
+	}
+
+	runDir := filepath.Join("logs", "runs")
+	if err := os.MkdirAll(runDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create run log directory: %v", err)
 	}
 
-	// Create structured logger that writes to both file and stdout
-	Logger = slog.New(slog.NewTextHandler(logFile, &slog.HandlerOptions{
-		Level: slog.LevelInfo,
-	}))
+	path := filepath.Join(runDir, fmt.Sprintf("%s-%s.log", time.Now().UTC().Format("20060102T150405Z"), id))
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open run log file %s: %v", path, err)
+	}
+
+	tee := io.MultiWriter(baseWriter, file)
+	handler, err := buildHandler(tee, logFormat, logLevel, logRedact)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to configure run log redaction: %v", err)
+	}
+	runLogger := slog.New(handler).With("corr_id", id)
+
+	return &Run{Logger: runLogger, ID: id, file: file}, nil
+}
+
+// Close releases the run's per-run log file. It does not affect the
+// package logger or other runs.
+func (r *Run) Close() error {
+	return r.file.Close()
+}
+
+func newCorrelationID() (string, error) {
+	buf := make([]byte, 4)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
 }
 
 func Info(msg string, args ...any) {
@@ -38,4 +188,4 @@ func Debug(msg string, args ...any) {
 
 func Warn(msg string, args ...any) {
 	Logger.Warn(msg, args...)
-}
\ No newline at end of file
+}
@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// RedactConfig configures redactingHandler's attribute scrubbing:
+// secret-env-var masking, pattern-based redaction, and long-value
+// truncation, all applied before a record reaches the underlying
+// handler (text/JSON, rotating or not).
+type RedactConfig struct {
+	// Patterns are additional regexes, beyond the secret-env-var scan,
+	// whose matches are replaced with "[REDACTED]" in every string
+	// attribute value.
+	Patterns []string `toml:"patterns"`
+	// SecretEnvVars lists environment variable names whose current value
+	// (if set) is masked wherever it appears in a logged attribute, e.g.
+	// "GEMINI_API_KEY". Unset or empty variables are skipped.
+	SecretEnvVars []string `toml:"secret_env_vars"`
+	// MaxAttrLen truncates a string attribute value past this length,
+	// appending "...(N more bytes)". Zero or negative disables
+	// truncation.
+	MaxAttrLen int `toml:"max_attr_len"`
+}
+
+const redactedPlaceholder = "[REDACTED]"
+
+// redactingHandler wraps an slog.Handler, scrubbing secrets and oversized
+// values out of every record's attributes before passing it on, so
+// whatever handler backs Logger or a Run (rotating file, JSON, stderr)
+// never has to know redaction exists. This is the middleware the AI
+// providers' full-prompt/full-response Debug logs rely on to stay safe
+// to collect and forward to Loki/ELK.
+type redactingHandler struct {
+	next     slog.Handler
+	patterns []*regexp.Regexp
+	secrets  []string
+	maxLen   int
+}
+
+func newRedactingHandler(next slog.Handler, cfg RedactConfig) (*redactingHandler, error) {
+	h := &redactingHandler{next: next, maxLen: cfg.MaxAttrLen}
+
+	for _, p := range cfg.Patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %v", p, err)
+		}
+		h.patterns = append(h.patterns, re)
+	}
+
+	for _, envVar := range cfg.SecretEnvVars {
+		if v := os.Getenv(envVar); v != "" {
+			h.secrets = append(h.secrets, v)
+		}
+	}
+
+	return h, nil
+}
+
+func (h *redactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *redactingHandler) Handle(ctx context.Context, record slog.Record) error {
+	scrubbed := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	record.Attrs(func(a slog.Attr) bool {
+		scrubbed.AddAttrs(h.scrub(a))
+		return true
+	})
+	return h.next.Handle(ctx, scrubbed)
+}
+
+func (h *redactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	scrubbed := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		scrubbed[i] = h.scrub(a)
+	}
+	return &redactingHandler{next: h.next.WithAttrs(scrubbed), patterns: h.patterns, secrets: h.secrets, maxLen: h.maxLen}
+}
+
+func (h *redactingHandler) WithGroup(name string) slog.Handler {
+	return &redactingHandler{next: h.next.WithGroup(name), patterns: h.patterns, secrets: h.secrets, maxLen: h.maxLen}
+}
+
+// scrub masks secrets and user patterns out of a string attribute, then
+// truncates it if it's still over maxLen. Non-string attributes (ints,
+// durations, errors, ...) pass through untouched.
+func (h *redactingHandler) scrub(a slog.Attr) slog.Attr {
+	if a.Value.Kind() != slog.KindString {
+		return a
+	}
+	s := a.Value.String()
+
+	for _, secret := range h.secrets {
+		s = strings.ReplaceAll(s, secret, redactedPlaceholder)
+	}
+	for _, re := range h.patterns {
+		s = re.ReplaceAllString(s, redactedPlaceholder)
+	}
+	if h.maxLen > 0 && len(s) > h.maxLen {
+		s = fmt.Sprintf("%s...(%d more bytes)", s[:h.maxLen], len(s)-h.maxLen)
+	}
+
+	return slog.String(a.Key, s)
+}
@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+)
+
+func TestRedactingHandlerScrubMasksSecretEnvVar(t *testing.T) {
+	const envVar = "SHEETFMT_TEST_SECRET"
+	os.Setenv(envVar, "sk-super-secret")
+	defer os.Unsetenv(envVar)
+
+	h, err := newRedactingHandler(slog.NewTextHandler(os.Stdout, nil), RedactConfig{SecretEnvVars: []string{envVar}})
+	if err != nil {
+		t.Fatalf("newRedactingHandler: %v", err)
+	}
+
+	got := h.scrub(slog.String("prompt", "key is sk-super-secret, please use it"))
+	want := "key is [REDACTED], please use it"
+	if got.Value.String() != want {
+		t.Errorf("scrub = %q, want %q", got.Value.String(), want)
+	}
+}
+
+func TestRedactingHandlerScrubUnsetSecretEnvVarIsSkipped(t *testing.T) {
+	os.Unsetenv("SHEETFMT_TEST_UNSET_SECRET")
+
+	h, err := newRedactingHandler(slog.NewTextHandler(os.Stdout, nil), RedactConfig{SecretEnvVars: []string{"SHEETFMT_TEST_UNSET_SECRET"}})
+	if err != nil {
+		t.Fatalf("newRedactingHandler: %v", err)
+	}
+
+	got := h.scrub(slog.String("msg", "nothing to redact here"))
+	if got.Value.String() != "nothing to redact here" {
+		t.Errorf("scrub = %q, want unchanged value", got.Value.String())
+	}
+}
+
+func TestRedactingHandlerScrubAppliesPatterns(t *testing.T) {
+	h, err := newRedactingHandler(slog.NewTextHandler(os.Stdout, nil), RedactConfig{
+		Patterns: []string{`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`},
+	})
+	if err != nil {
+		t.Fatalf("newRedactingHandler: %v", err)
+	}
+
+	got := h.scrub(slog.String("msg", "contact jane@example.com for details"))
+	want := "contact [REDACTED] for details"
+	if got.Value.String() != want {
+		t.Errorf("scrub = %q, want %q", got.Value.String(), want)
+	}
+}
+
+func TestRedactingHandlerScrubInvalidPatternErrors(t *testing.T) {
+	_, err := newRedactingHandler(slog.NewTextHandler(os.Stdout, nil), RedactConfig{Patterns: []string{"("}})
+	if err == nil {
+		t.Fatal("expected an error for an invalid regex pattern, got nil")
+	}
+}
+
+func TestRedactingHandlerScrubTruncatesOversizedValues(t *testing.T) {
+	h, err := newRedactingHandler(slog.NewTextHandler(os.Stdout, nil), RedactConfig{MaxAttrLen: 5})
+	if err != nil {
+		t.Fatalf("newRedactingHandler: %v", err)
+	}
+
+	got := h.scrub(slog.String("body", "0123456789"))
+	want := "01234...(5 more bytes)"
+	if got.Value.String() != want {
+		t.Errorf("scrub = %q, want %q", got.Value.String(), want)
+	}
+}
+
+func TestRedactingHandlerScrubZeroMaxAttrLenDisablesTruncation(t *testing.T) {
+	h, err := newRedactingHandler(slog.NewTextHandler(os.Stdout, nil), RedactConfig{})
+	if err != nil {
+		t.Fatalf("newRedactingHandler: %v", err)
+	}
+
+	long := "0123456789"
+	got := h.scrub(slog.String("body", long))
+	if got.Value.String() != long {
+		t.Errorf("scrub = %q, want unchanged %q", got.Value.String(), long)
+	}
+}
+
+func TestRedactingHandlerScrubIgnoresNonStringAttrs(t *testing.T) {
+	h, err := newRedactingHandler(slog.NewTextHandler(os.Stdout, nil), RedactConfig{MaxAttrLen: 1})
+	if err != nil {
+		t.Fatalf("newRedactingHandler: %v", err)
+	}
+
+	got := h.scrub(slog.Int("count", 12345))
+	if got.Value.Int64() != 12345 {
+		t.Errorf("scrub modified a non-string attr: got %v", got.Value)
+	}
+}
@@ -0,0 +1,209 @@
+package mapping
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"sheetFmt/internal/logger"
+)
+
+func init() {
+	RegisterProvider("anthropic", newAnthropicProvider)
+}
+
+const anthropicDefaultModel = "claude-3-5-haiku-latest"
+const anthropicDefaultBaseURL = "https://api.anthropic.com/v1"
+const anthropicDefaultAPIKeyEnvVar = "ANTHROPIC_API_KEY"
+const anthropicAPIVersion = "2023-06-01"
+const anthropicDefaultTimeout = 90 * time.Second
+const anthropicDefaultTemperature = 0.1
+
+// anthropicProvider talks to Anthropic's Messages API.
+type anthropicProvider struct {
+	apiKey      string
+	baseURL     string
+	model       string
+	temperature float64
+	maxRetries  int
+	client      *http.Client
+}
+
+func newAnthropicProvider(cfg ProviderConfig) (AIProvider, error) {
+	envVar := cfg.APIKeyEnvVar
+	if envVar == "" {
+		envVar = anthropicDefaultAPIKeyEnvVar
+	}
+	apiKey := os.Getenv(envVar)
+	if apiKey == "" {
+		return nil, fmt.Errorf("anthropic API key is required (set %s)", envVar)
+	}
+
+	baseURL := cfg.Endpoint
+	if baseURL == "" {
+		baseURL = anthropicDefaultBaseURL
+	}
+
+	model := cfg.Model
+	if model == "" {
+		model = anthropicDefaultModel
+	}
+
+	temperature := cfg.Temperature
+	if temperature == 0 {
+		temperature = anthropicDefaultTemperature
+	}
+
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = anthropicDefaultTimeout
+	}
+
+	return &anthropicProvider{
+		apiKey:      apiKey,
+		baseURL:     baseURL,
+		model:       model,
+		temperature: temperature,
+		maxRetries:  cfg.MaxRetries,
+		client:      &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func (a *anthropicProvider) Name() string { return "anthropic" }
+
+func (a *anthropicProvider) Close() error { return nil }
+
+// anthropicMappingToolName names the single tool the request forces the model to
+// call, giving us the same schema-constrained decoding the other providers
+// get natively from their APIs: Claude can only "respond" by filling in
+// this tool's input, which the Messages API validates against the schema
+// before it ever reaches us.
+const anthropicMappingToolName = "report_column_mappings"
+
+type anthropicMessagesRequest struct {
+	Model       string              `json:"model"`
+	MaxTokens   int                 `json:"max_tokens"`
+	Temperature float64             `json:"temperature"`
+	Messages    []anthropicMessage  `json:"messages"`
+	Tools       []anthropicTool     `json:"tools"`
+	ToolChoice  anthropicToolChoice `json:"tool_choice"`
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicTool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"input_schema"`
+}
+
+type anthropicToolChoice struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+type anthropicMessagesResponse struct {
+	Content []struct {
+		Type  string          `json:"type"`
+		Text  string          `json:"text"`
+		Name  string          `json:"name"`
+		Input json.RawMessage `json:"input"`
+	} `json:"content"`
+}
+
+func (a *anthropicProvider) GenerateColumnMappings(ctx context.Context, scannedColumns, targetColumns []string) ([]AIMapping, error) {
+	if len(scannedColumns) == 0 || len(targetColumns) == 0 {
+		return nil, fmt.Errorf("both scanned and target columns must be provided")
+	}
+
+	if len(scannedColumns) > 100 {
+		return chunkAndGenerate(scannedColumns, targetColumns, 50, func(chunk []string) ([]AIMapping, error) {
+			return a.generateSingleBatch(ctx, chunk, targetColumns)
+		})
+	}
+
+	return a.generateSingleBatch(ctx, scannedColumns, targetColumns)
+}
+
+func (a *anthropicProvider) generateSingleBatch(ctx context.Context, scannedColumns, targetColumns []string) ([]AIMapping, error) {
+	prompt := buildMappingPrompt(scannedColumns, targetColumns)
+
+	var mappings []AIMapping
+	err := retryWithBackoff(ctx, a.maxRetries, func() error {
+		result, err := a.callAPI(ctx, prompt, targetColumns)
+		if err != nil {
+			return err
+		}
+		mappings = result
+		return nil
+	})
+	return mappings, err
+}
+
+func (a *anthropicProvider) callAPI(ctx context.Context, prompt string, targetColumns []string) ([]AIMapping, error) {
+	reqBody := anthropicMessagesRequest{
+		Model:       a.model,
+		MaxTokens:   4096,
+		Temperature: a.temperature,
+		Messages: []anthropicMessage{
+			{Role: "user", Content: prompt},
+		},
+		Tools: []anthropicTool{
+			{
+				Name:        anthropicMappingToolName,
+				Description: "Reports the column mappings found for the scanned columns.",
+				InputSchema: mappingResponseSchema,
+			},
+		},
+		ToolChoice: anthropicToolChoice{Type: "tool", Name: anthropicMappingToolName},
+	}
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode Anthropic request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.baseURL+"/messages", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build Anthropic request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", a.apiKey)
+	req.Header.Set("anthropic-version", anthropicAPIVersion)
+
+	logger.Info("Sending request to Anthropic API", "model", a.model)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Anthropic request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Anthropic response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Anthropic API returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var parsed anthropicMessagesResponse
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode Anthropic response: %v", err)
+	}
+
+	for _, block := range parsed.Content {
+		if block.Type == "tool_use" && block.Name == anthropicMappingToolName {
+			return parseMappingResponse(string(block.Input), targetColumns)
+		}
+	}
+
+	return nil, fmt.Errorf("Anthropic response did not include a %s tool call", anthropicMappingToolName)
+}
@@ -0,0 +1,95 @@
+package mapping
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// defaultEmbeddingCachePath is where openEmbeddingCache looks when the
+// caller leaves a path blank, mirroring defaultSynonymsPath's configs/
+// convention.
+const defaultEmbeddingCachePath = "configs/embedding_cache.db"
+
+var embeddingCacheBucket = []byte("embeddings")
+
+// embeddingCache persists stage 3 embedding vectors across runs in a
+// single-file BoltDB, so re-mapping similar sheets doesn't re-pay an
+// embeddings API call for a column name it's already seen. Keys combine
+// the embedding provider's name with the column's normalized form, since
+// different providers' vectors aren't comparable.
+type embeddingCache struct {
+	db *bolt.DB
+}
+
+// openEmbeddingCache opens (creating if necessary) the BoltDB file at
+// path, or defaultEmbeddingCachePath if path is blank.
+func openEmbeddingCache(path string) (*embeddingCache, error) {
+	if path == "" {
+		path = defaultEmbeddingCachePath
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create embedding cache directory: %v", err)
+	}
+
+	db, err := bolt.Open(path, 0644, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open embedding cache %s: %v", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(embeddingCacheBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize embedding cache bucket: %v", err)
+	}
+
+	return &embeddingCache{db: db}, nil
+}
+
+func (c *embeddingCache) Close() error {
+	return c.db.Close()
+}
+
+func cacheKey(provider, normalized string) []byte {
+	return []byte(provider + "\x00" + normalized)
+}
+
+// get returns the cached vector for (provider, normalized), if any.
+func (c *embeddingCache) get(provider, normalized string) ([]float64, bool) {
+	var vec []float64
+	var found bool
+
+	c.db.View(func(tx *bolt.Tx) error {
+		raw := tx.Bucket(embeddingCacheBucket).Get(cacheKey(provider, normalized))
+		if raw == nil {
+			return nil
+		}
+		if err := json.Unmarshal(raw, &vec); err != nil {
+			return nil
+		}
+		found = true
+		return nil
+	})
+
+	return vec, found
+}
+
+// put stores vec under (provider, normalized), overwriting any existing
+// entry.
+func (c *embeddingCache) put(provider, normalized string, vec []float64) error {
+	raw, err := json.Marshal(vec)
+	if err != nil {
+		return err
+	}
+
+	return c.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(embeddingCacheBucket).Put(cacheKey(provider, normalized), raw)
+	})
+}
@@ -0,0 +1,325 @@
+package mapping
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"time"
+
+	"sheetFmt/internal/logger"
+
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/option"
+)
+
+// EmbeddingProvider computes embedding vectors for a batch of strings, for
+// hybridProvider's stage 3 cosine-similarity matching. It's a separate,
+// smaller interface from AIProvider: an embeddings call takes plain
+// strings in and vectors out, no prompt or mapping semantics involved.
+type EmbeddingProvider interface {
+	// Name identifies the provider, used as part of the embeddingCache key
+	// so vectors from different embedding spaces never collide.
+	Name() string
+	// Embed returns one vector per entry in texts, in the same order.
+	Embed(ctx context.Context, texts []string) ([][]float64, error)
+	Close() error
+}
+
+// embeddingRegistry mirrors providerRegistry in provider.go, but for
+// EmbeddingProvider factories.
+var embeddingRegistry = map[string]func(cfg ProviderConfig) (EmbeddingProvider, error){}
+
+// RegisterEmbeddingProvider adds a named embedding factory to the
+// registry, the same init()-time pattern AIProvider implementations use
+// via RegisterProvider.
+func RegisterEmbeddingProvider(name string, factory func(cfg ProviderConfig) (EmbeddingProvider, error)) {
+	embeddingRegistry[name] = factory
+}
+
+// NewEmbeddingProvider constructs the named embedding backend via its
+// registered factory. It's looked up separately from NewProvider's
+// providerRegistry since not every AI provider also exposes an embeddings
+// endpoint under the same name (anthropic doesn't, for instance).
+func NewEmbeddingProvider(name string, cfg ProviderConfig) (EmbeddingProvider, error) {
+	factory, ok := embeddingRegistry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown embedding provider: %s", name)
+	}
+	return factory(cfg)
+}
+
+// cosineSimilarity is the cosine of the angle between a and b, or 0 if
+// either is a zero vector or they differ in length.
+func cosineSimilarity(a, b []float64) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+func init() {
+	RegisterEmbeddingProvider("gemini", newGeminiEmbedder)
+	RegisterEmbeddingProvider("openai", newOpenAIEmbedder)
+	RegisterEmbeddingProvider("ollama", newOllamaEmbedder)
+}
+
+const geminiEmbeddingModel = "text-embedding-004"
+
+// geminiEmbedder calls Gemini's embedding endpoint.
+type geminiEmbedder struct {
+	client *genai.Client
+	model  string
+}
+
+func newGeminiEmbedder(cfg ProviderConfig) (EmbeddingProvider, error) {
+	envVar := cfg.APIKeyEnvVar
+	if envVar == "" {
+		envVar = geminiDefaultAPIKeyEnvVar
+	}
+	apiKey := os.Getenv(envVar)
+	if apiKey == "" {
+		return nil, fmt.Errorf("gemini API key is required (set %s)", envVar)
+	}
+
+	client, err := genai.NewClient(context.Background(), option.WithAPIKey(apiKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Gemini client: %v", err)
+	}
+
+	model := cfg.Model
+	if model == "" {
+		model = geminiEmbeddingModel
+	}
+	return &geminiEmbedder{client: client, model: model}, nil
+}
+
+func (g *geminiEmbedder) Name() string { return "gemini:" + g.model }
+
+func (g *geminiEmbedder) Close() error { return g.client.Close() }
+
+func (g *geminiEmbedder) Embed(ctx context.Context, texts []string) ([][]float64, error) {
+	em := g.client.EmbeddingModel(g.model)
+	batch := em.NewBatch()
+	for _, text := range texts {
+		batch.AddContent(genai.Text(text))
+	}
+
+	resp, err := em.BatchEmbedContents(ctx, batch)
+	if err != nil {
+		return nil, fmt.Errorf("Gemini embedding request failed: %v", err)
+	}
+	if len(resp.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("Gemini returned %d embeddings for %d inputs", len(resp.Embeddings), len(texts))
+	}
+
+	vecs := make([][]float64, len(texts))
+	for i, e := range resp.Embeddings {
+		vecs[i] = float32SliceToFloat64(e.Values)
+	}
+	return vecs, nil
+}
+
+const openAIEmbeddingModel = "text-embedding-3-small"
+const openAIEmbeddingDefaultTimeout = 30 * time.Second
+
+// openAIEmbedder calls OpenAI's /embeddings endpoint.
+type openAIEmbedder struct {
+	apiKey  string
+	baseURL string
+	model   string
+	client  *http.Client
+}
+
+func newOpenAIEmbedder(cfg ProviderConfig) (EmbeddingProvider, error) {
+	envVar := cfg.APIKeyEnvVar
+	if envVar == "" {
+		envVar = openAIDefaultAPIKeyEnvVar
+	}
+	apiKey := os.Getenv(envVar)
+	if apiKey == "" {
+		return nil, fmt.Errorf("openai API key is required (set %s)", envVar)
+	}
+
+	baseURL := cfg.Endpoint
+	if baseURL == "" {
+		baseURL = openAIDefaultBaseURL
+	}
+	model := cfg.Model
+	if model == "" {
+		model = openAIEmbeddingModel
+	}
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = openAIEmbeddingDefaultTimeout
+	}
+
+	return &openAIEmbedder{apiKey: apiKey, baseURL: baseURL, model: model, client: &http.Client{Timeout: timeout}}, nil
+}
+
+func (o *openAIEmbedder) Name() string { return "openai:" + o.model }
+
+func (o *openAIEmbedder) Close() error { return nil }
+
+type openAIEmbeddingRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type openAIEmbeddingResponse struct {
+	Data []struct {
+		Embedding []float64 `json:"embedding"`
+		Index     int       `json:"index"`
+	} `json:"data"`
+}
+
+func (o *openAIEmbedder) Embed(ctx context.Context, texts []string) ([][]float64, error) {
+	body, err := json.Marshal(openAIEmbeddingRequest{Model: o.model, Input: texts})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode OpenAI embedding request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.baseURL+"/embeddings", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build OpenAI embedding request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+o.apiKey)
+
+	logger.Info("Sending embedding request to OpenAI API", "model", o.model, "count", len(texts))
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("OpenAI embedding request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OpenAI embeddings API returned status %d", resp.StatusCode)
+	}
+
+	var parsed openAIEmbeddingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode OpenAI embedding response: %v", err)
+	}
+
+	vecs := make([][]float64, len(texts))
+	for _, entry := range parsed.Data {
+		if entry.Index < 0 || entry.Index >= len(vecs) {
+			continue
+		}
+		vecs[entry.Index] = entry.Embedding
+	}
+	return vecs, nil
+}
+
+const ollamaEmbeddingDefaultModel = "nomic-embed-text"
+
+// ollamaEmbedder calls a local Ollama server's /api/embeddings endpoint,
+// intended for a local sentence-transformer model rather than a cloud
+// embeddings API.
+type ollamaEmbedder struct {
+	host   string
+	model  string
+	client *http.Client
+}
+
+func newOllamaEmbedder(cfg ProviderConfig) (EmbeddingProvider, error) {
+	host := cfg.Endpoint
+	if host == "" {
+		host = os.Getenv("OLLAMA_HOST")
+	}
+	if host == "" {
+		host = ollamaDefaultHost
+	}
+	model := cfg.Model
+	if model == "" {
+		model = ollamaEmbeddingDefaultModel
+	}
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = ollamaDefaultTimeout
+	}
+
+	return &ollamaEmbedder{host: host, model: model, client: &http.Client{Timeout: timeout}}, nil
+}
+
+func (o *ollamaEmbedder) Name() string { return "ollama:" + o.model }
+
+func (o *ollamaEmbedder) Close() error { return nil }
+
+type ollamaEmbeddingRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+}
+
+type ollamaEmbeddingResponse struct {
+	Embedding []float64 `json:"embedding"`
+}
+
+// Embed calls Ollama's embeddings endpoint once per text: unlike Gemini
+// and OpenAI it doesn't accept a batch of inputs in one request.
+func (o *ollamaEmbedder) Embed(ctx context.Context, texts []string) ([][]float64, error) {
+	vecs := make([][]float64, len(texts))
+	for i, text := range texts {
+		vec, err := o.embedOne(ctx, text)
+		if err != nil {
+			return nil, err
+		}
+		vecs[i] = vec
+	}
+	return vecs, nil
+}
+
+func (o *ollamaEmbedder) embedOne(ctx context.Context, text string) ([]float64, error) {
+	body, err := json.Marshal(ollamaEmbeddingRequest{Model: o.model, Prompt: text})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode Ollama embedding request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.host+"/api/embeddings", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build Ollama embedding request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Ollama embedding request failed (is `ollama serve` running?): %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Ollama embeddings API returned status %d", resp.StatusCode)
+	}
+
+	var parsed ollamaEmbeddingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode Ollama embedding response: %v", err)
+	}
+	return parsed.Embedding, nil
+}
+
+// float32SliceToFloat64 converts Gemini's []float32 embedding values to
+// the []float64 every EmbeddingProvider reports, so cosineSimilarity and
+// embeddingCache don't need to special-case one provider's precision.
+func float32SliceToFloat64(values []float32) []float64 {
+	out := make([]float64, len(values))
+	for i, v := range values {
+		out[i] = float64(v)
+	}
+	return out
+}
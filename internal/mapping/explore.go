@@ -0,0 +1,538 @@
+package mapping
+
+import (
+	"fmt"
+	"math"
+	"sheetFmt/internal/logger"
+	"sheetFmt/internal/table"
+	"sheetFmt/internal/tabular"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// exploreState drives the explore TUI's small state machine: browsing the
+// grid, inspecting one cell's full content, typing a ':' command, or
+// borrowing the mapping tool's target-selection flow for the highlighted
+// column.
+type exploreState int
+
+const (
+	exploreBrowse exploreState = iota
+	exploreInspect
+	exploreCommand
+	exploreSelectTarget
+)
+
+// exploreModel is the Bubble Tea model behind RunExploreTUI. It wraps a
+// table.Model for the virtualized grid and reuses the same mappings/
+// ignored maps and MappingConfig persistence as the column-picker TUI, so
+// mapping decisions made while looking at real data end up in the same
+// column_mapping.json file.
+type exploreModel struct {
+	tbl table.Model
+
+	mappings map[string]string // scanned -> target
+	ignored  map[string]bool   // scanned -> ignored
+
+	targetColumns     []string
+	outputMappingFile string
+
+	state exploreState
+
+	// :command mode
+	cmdInput string
+	cmdErr   string
+
+	// target-selection flow, entered with 'm' from a highlighted column
+	currentScanned string
+	targetCursor   int
+	targetPage     int
+	targetPerPage  int
+
+	width  int
+	height int
+
+	titleStyle    lipgloss.Style
+	helpStyle     lipgloss.Style
+	statusStyle   lipgloss.Style
+	errorStyle    lipgloss.Style
+	selectedStyle lipgloss.Style
+	normalStyle   lipgloss.Style
+	mappedStyle   lipgloss.Style
+}
+
+func initialExploreModel(headers []string, rows [][]string, targetColumns []string, mappings map[string]string, ignored map[string]bool, outputMappingFile string) exploreModel {
+	return exploreModel{
+		tbl:               table.NewModel(headers, rows),
+		mappings:          mappings,
+		ignored:           ignored,
+		targetColumns:     targetColumns,
+		outputMappingFile: outputMappingFile,
+		state:             exploreBrowse,
+		targetPerPage:     15,
+
+		titleStyle: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("205")),
+		helpStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("241")),
+		statusStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("205")).
+			Bold(true),
+		errorStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")),
+		selectedStyle: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("170")).
+			Background(lipgloss.Color("235")).
+			Padding(0, 1),
+		normalStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("252")).
+			Padding(0, 1),
+		mappedStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("40")).
+			Padding(0, 1),
+	}
+}
+
+func (m exploreModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m exploreModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		// Leave room for the title, status line, and help footer.
+		m.tbl.SetSize(msg.Width, msg.Height-6)
+		return m, nil
+
+	case tea.KeyMsg:
+		switch m.state {
+		case exploreBrowse:
+			return m.updateBrowse(msg)
+		case exploreInspect:
+			return m.updateInspect(msg)
+		case exploreCommand:
+			return m.updateCommand(msg)
+		case exploreSelectTarget:
+			return m.updateSelectTarget(msg)
+		}
+	}
+	return m, nil
+}
+
+func (m exploreModel) updateBrowse(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+
+	case "up", "k":
+		m.tbl.MoveCursor(-1, 0)
+	case "down", "j":
+		m.tbl.MoveCursor(1, 0)
+	case "left", "h":
+		m.tbl.MoveCursor(0, -1)
+	case "right", "l":
+		m.tbl.MoveCursor(0, 1)
+
+	case "enter":
+		m.state = exploreInspect
+
+	case "m":
+		m.enterTargetSelection()
+
+	case ":":
+		m.state = exploreCommand
+		m.cmdInput = ""
+		m.cmdErr = ""
+
+	case "s":
+		if err := m.save(); err != nil {
+			m.cmdErr = err.Error()
+		}
+	}
+	return m, nil
+}
+
+func (m exploreModel) updateInspect(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q", "esc", "enter":
+		m.state = exploreBrowse
+	}
+	return m, nil
+}
+
+func (m exploreModel) updateCommand(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC, tea.KeyEsc:
+		m.state = exploreBrowse
+		return m, nil
+	case tea.KeyEnter:
+		m.runCommand(strings.TrimSpace(m.cmdInput))
+		m.cmdInput = ""
+		return m, nil
+	case tea.KeyBackspace:
+		if len(m.cmdInput) > 0 {
+			m.cmdInput = m.cmdInput[:len(m.cmdInput)-1]
+		}
+		return m, nil
+	case tea.KeyRunes:
+		m.cmdInput += string(msg.Runes)
+		return m, nil
+	}
+	return m, nil
+}
+
+// runCommand parses and executes one ':' command, leaving the result (or
+// an error) for the status line and, unless it was :map, returning to
+// browse mode.
+func (m *exploreModel) runCommand(cmd string) {
+	m.cmdErr = ""
+	m.state = exploreBrowse
+
+	fields := strings.Fields(cmd)
+	if len(fields) == 0 {
+		m.state = exploreBrowse
+		return
+	}
+
+	switch fields[0] {
+	case "goto":
+		if len(fields) != 2 {
+			m.cmdErr = "usage: :goto <row>"
+			return
+		}
+		n, err := strconv.Atoi(fields[1])
+		if err != nil || !m.tbl.GotoRow(n-1) {
+			m.cmdErr = fmt.Sprintf("no such row: %s", fields[1])
+			return
+		}
+		m.state = exploreBrowse
+
+	case "find":
+		if len(fields) < 2 {
+			m.cmdErr = "usage: :find <text>"
+			return
+		}
+		needle := strings.Join(fields[1:], " ")
+		if idx, ok := m.tbl.Find(needle); ok {
+			m.tbl.GotoRow(idx)
+			m.state = exploreBrowse
+		} else {
+			m.cmdErr = fmt.Sprintf("not found: %s", needle)
+		}
+
+	case "filter":
+		if len(fields) < 2 || fields[1] == "clear" {
+			m.cmdErr = ""
+			m.state = exploreBrowse
+			return
+		}
+		expr := strings.Join(fields[1:], " ")
+		col, value, ok := strings.Cut(expr, "=")
+		if !ok {
+			m.cmdErr = "usage: :filter <column>=<text>"
+			return
+		}
+		if err := m.applyFilter(strings.TrimSpace(col), strings.TrimSpace(value)); err != nil {
+			m.cmdErr = err.Error()
+			return
+		}
+		m.state = exploreBrowse
+
+	case "map":
+		m.enterTargetSelection()
+
+	default:
+		m.cmdErr = fmt.Sprintf("unknown command: %s", fields[0])
+	}
+}
+
+func (m *exploreModel) enterTargetSelection() {
+	header := m.tbl.CursorHeader()
+	if header == "" {
+		return
+	}
+	m.currentScanned = header
+	m.state = exploreSelectTarget
+
+	if target, mapped := m.mappings[header]; mapped {
+		for i, t := range m.targetColumns {
+			if t == target {
+				m.targetPage = i / m.targetPerPage
+				m.targetCursor = i % m.targetPerPage
+				return
+			}
+		}
+	}
+	m.targetCursor = 0
+	m.targetPage = 0
+}
+
+func (m exploreModel) updateSelectTarget(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc":
+		m.state = exploreBrowse
+	case "up", "k":
+		if m.targetCursor > 0 {
+			m.targetCursor--
+		} else if m.targetPage > 0 {
+			m.targetPage--
+			m.targetCursor = m.targetPerPage - 1
+		}
+	case "down", "j":
+		maxCursor := m.maxTargetCursor()
+		if m.targetCursor < maxCursor {
+			m.targetCursor++
+		} else if m.hasNextTargetPage() {
+			m.targetPage++
+			m.targetCursor = 0
+		}
+	case "left", "h":
+		if m.targetPage > 0 {
+			m.targetPage--
+		}
+	case "right", "l":
+		if m.hasNextTargetPage() {
+			m.targetPage++
+		}
+	case "enter":
+		targetIdx := m.targetPage*m.targetPerPage + m.targetCursor
+		if targetIdx < len(m.targetColumns) {
+			m.mappings[m.currentScanned] = m.targetColumns[targetIdx]
+			delete(m.ignored, m.currentScanned)
+			m.state = exploreBrowse
+		}
+	}
+	return m, nil
+}
+
+func (m exploreModel) maxTargetCursor() int {
+	itemsOnPage := len(m.targetColumns) - m.targetPage*m.targetPerPage
+	if itemsOnPage > m.targetPerPage {
+		return m.targetPerPage - 1
+	}
+	return itemsOnPage - 1
+}
+
+func (m exploreModel) hasNextTargetPage() bool {
+	return (m.targetPage+1)*m.targetPerPage < len(m.targetColumns)
+}
+
+// applyFilter finds a column by name and narrows the displayed rows to
+// those whose value contains the given substring (case-insensitive).
+// There is no separate "unfiltered" snapshot kept here: :filter clear just
+// leaves the current rows in place, so filtering is a per-session
+// narrowing rather than a saved view.
+func (m *exploreModel) applyFilter(column, value string) error {
+	colIdx := -1
+	for i, h := range m.tbl.Headers() {
+		if h == column {
+			colIdx = i
+			break
+		}
+	}
+	if colIdx == -1 {
+		return fmt.Errorf("unknown column: %s", column)
+	}
+
+	var filtered [][]string
+	for i := 0; i < m.tbl.RowCount(); i++ {
+		row := m.tbl.Row(i)
+		if colIdx < len(row) && strings.Contains(strings.ToLower(row[colIdx]), strings.ToLower(value)) {
+			filtered = append(filtered, row)
+		}
+	}
+	m.tbl.SetRows(filtered)
+	return nil
+}
+
+func (m *exploreModel) save() error {
+	config := &MappingConfig{}
+	for scanned, target := range m.mappings {
+		config.Mappings = append(config.Mappings, ColumnMapping{
+			ScannedColumn: scanned,
+			TargetColumn:  target,
+		})
+	}
+	for scanned := range m.ignored {
+		config.Mappings = append(config.Mappings, ColumnMapping{
+			ScannedColumn: scanned,
+			IsIgnored:     true,
+		})
+	}
+	return config.SaveToFile(m.outputMappingFile)
+}
+
+func (m exploreModel) View() string {
+	var b strings.Builder
+
+	title := m.titleStyle.Render("Sheet Explorer")
+	b.WriteString(title)
+	b.WriteString("\n\n")
+
+	b.WriteString(m.tbl.View())
+	b.WriteString("\n\n")
+
+	status := fmt.Sprintf("Row %d/%d  Col %s  Mapped %d  Ignored %d",
+		m.tbl.CursorRow()+1, m.tbl.RowCount(), m.tbl.CursorHeader(), len(m.mappings), len(m.ignored))
+	b.WriteString(m.statusStyle.Render(status))
+	b.WriteString("\n\n")
+
+	switch m.state {
+	case exploreInspect:
+		b.WriteString(m.viewInspect())
+	case exploreCommand:
+		b.WriteString(m.viewCommand())
+	case exploreSelectTarget:
+		b.WriteString(m.viewSelectTarget())
+	default:
+		if m.cmdErr != "" {
+			b.WriteString(m.errorStyle.Render(m.cmdErr))
+			b.WriteString("\n")
+		}
+		help := "↑↓←→/hjkl: move | Enter: inspect cell | m: map column | :: command | s: save | q: quit"
+		b.WriteString(m.helpStyle.Render(help))
+	}
+
+	return b.String()
+}
+
+func (m exploreModel) viewInspect() string {
+	var b strings.Builder
+	b.WriteString(m.titleStyle.Render(fmt.Sprintf("%s (row %d)", m.tbl.CursorHeader(), m.tbl.CursorRow()+1)))
+	b.WriteString("\n\n")
+	b.WriteString(m.normalStyle.Render(m.tbl.CursorValue()))
+	b.WriteString("\n\n")
+	b.WriteString(m.helpStyle.Render("Esc/Enter: back"))
+	return b.String()
+}
+
+func (m exploreModel) viewCommand() string {
+	var b strings.Builder
+	b.WriteString(":" + m.cmdInput)
+	b.WriteString("\n")
+	b.WriteString(m.helpStyle.Render("Commands: :goto <row> | :find <text> | :filter <col>=<text> | :filter clear | :map | Esc: cancel"))
+	return b.String()
+}
+
+func (m exploreModel) viewSelectTarget() string {
+	var b strings.Builder
+
+	title := fmt.Sprintf("Map '%s' to target column:", m.currentScanned)
+	b.WriteString(m.titleStyle.Render(title))
+	b.WriteString("\n\n")
+
+	totalPages := int(math.Ceil(float64(len(m.targetColumns)) / float64(m.targetPerPage)))
+	if totalPages == 0 {
+		totalPages = 1
+	}
+	b.WriteString(m.helpStyle.Render(fmt.Sprintf("Page %d/%d", m.targetPage+1, totalPages)))
+	b.WriteString("\n\n")
+
+	start := m.targetPage * m.targetPerPage
+	end := start + m.targetPerPage
+	if end > len(m.targetColumns) {
+		end = len(m.targetColumns)
+	}
+
+	for i := start; i < end; i++ {
+		column := m.targetColumns[i]
+		localIndex := i - start
+
+		style := m.normalStyle
+		prefix := "  "
+		if localIndex == m.targetCursor {
+			style = m.selectedStyle
+			prefix = "> "
+		} else if column == m.mappings[m.currentScanned] {
+			style = m.mappedStyle
+		}
+
+		b.WriteString(style.Render(prefix + column))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.helpStyle.Render("↑↓: navigate | ←→: prev/next page | Enter: select | Esc: back | q: quit"))
+
+	return b.String()
+}
+
+// RunExploreTUI opens inputFile (XLSX or CSV/TSV, via the tabular
+// abstraction) and runs an interactive pager over its actual rows,
+// modeled after nushell's `explore`. From any highlighted column the user
+// can press 'm' to map it to a target column without leaving the data, so
+// mapping decisions reuse the same column_mapping.json as RunMappingTUI.
+func RunExploreTUI(inputFile, sheet, targetColumnsFile, outputMappingFile string) error {
+	reader, err := tabular.OpenReader(inputFile, sheet)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %v", inputFile, err)
+	}
+	defer reader.Close()
+
+	headers, err := reader.Headers()
+	if err != nil {
+		return fmt.Errorf("failed to read headers from %s: %v", inputFile, err)
+	}
+
+	rowIter, err := reader.Rows()
+	if err != nil {
+		return fmt.Errorf("failed to read rows from %s: %v", inputFile, err)
+	}
+	defer rowIter.Close()
+
+	var rows [][]string
+	for rowIter.Next() {
+		row, err := rowIter.Columns()
+		if err != nil {
+			return fmt.Errorf("failed to read row from %s: %v", inputFile, err)
+		}
+		rows = append(rows, row)
+	}
+	if err := rowIter.Err(); err != nil {
+		return fmt.Errorf("error iterating rows in %s: %v", inputFile, err)
+	}
+
+	targetColumns, err := ReadColumnsFromFile(targetColumnsFile)
+	if err != nil {
+		return fmt.Errorf("failed to read target columns: %v", err)
+	}
+
+	mappings := make(map[string]string)
+	ignored := make(map[string]bool)
+	if existingConfig, err := LoadFromFile(outputMappingFile); err == nil {
+		for _, mapping := range existingConfig.Mappings {
+			if mapping.IsIgnored {
+				ignored[mapping.ScannedColumn] = true
+			} else if mapping.TargetColumn != "" {
+				mappings[mapping.ScannedColumn] = mapping.TargetColumn
+			}
+		}
+	}
+
+	logger.Info("Starting explore TUI", "input_file", inputFile, "rows", len(rows), "columns", len(headers))
+
+	m := initialExploreModel(headers, rows, targetColumns, mappings, ignored, outputMappingFile)
+
+	p := tea.NewProgram(m, tea.WithAltScreen())
+	finalModel, err := p.Run()
+	if err != nil {
+		return fmt.Errorf("error running explore TUI: %v", err)
+	}
+
+	final := finalModel.(exploreModel)
+	if err := final.save(); err != nil {
+		return fmt.Errorf("failed to save mapping configuration: %v", err)
+	}
+
+	logger.Info("Explore TUI finished", "mapped", len(final.mappings), "ignored", len(final.ignored))
+	return nil
+}
@@ -0,0 +1,121 @@
+package mapping
+
+import "strings"
+
+// fuzzyScore ranks candidate against query the way Helix's file picker
+// does: a subsequence match is required to match at all, then the score
+// rewards contiguous runs, a bonus for matching at the very start of the
+// string, and bigram overlap with the candidate. Because the subsequence
+// match comes first, an abbreviation like "cust id" finds "Customer ID",
+// but a query whose words are in a different order than the candidate's
+// is dropped rather than scored.
+//
+// It reports ok=false when query is not a subsequence of candidate at
+// all, meaning the candidate should be dropped from the filtered list
+// rather than merely scored low.
+func fuzzyScore(query, candidate string) (float64, bool) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return 0, true
+	}
+
+	lower := strings.ToLower(candidate)
+	q := []rune(query)
+	c := []rune(lower)
+
+	if !isSubsequence(q, c) {
+		return 0, false
+	}
+
+	score := subsequenceRunScore(q, c)
+	if strings.HasPrefix(lower, query) {
+		score += 10
+	}
+	score += bigramOverlap(query, lower) * 6
+
+	return score, true
+}
+
+// isSubsequence reports whether every rune of query appears in candidate
+// in order, with gaps allowed.
+func isSubsequence(query, candidate []rune) bool {
+	qi := 0
+	for ci := 0; ci < len(candidate) && qi < len(query); ci++ {
+		if candidate[ci] == query[qi] {
+			qi++
+		}
+	}
+	return qi == len(query)
+}
+
+// subsequenceRunScore walks the best greedy alignment of query inside
+// candidate, rewarding contiguous runs (so "cust" beats "c-u-s-t"
+// scattered across the string) and penalizing the gaps between matches.
+func subsequenceRunScore(query, candidate []rune) float64 {
+	var score float64
+	qi, runLen, lastMatch := 0, 0, -1
+
+	for ci := 0; ci < len(candidate) && qi < len(query); ci++ {
+		if candidate[ci] != query[qi] {
+			continue
+		}
+
+		if lastMatch == ci-1 {
+			runLen++
+		} else {
+			runLen = 1
+		}
+		score += float64(runLen)
+
+		if lastMatch >= 0 {
+			gap := ci - lastMatch - 1
+			score -= float64(gap) * 0.25
+		}
+
+		lastMatch = ci
+		qi++
+	}
+
+	return score
+}
+
+// bigramOverlap returns the number of bigrams a and b share divided by
+// the larger of their two bigram counts, used to reward shared substrings
+// regardless of where they fall.
+func bigramOverlap(a, b string) float64 {
+	ag, bg := bigrams(a), bigrams(b)
+	if len(ag) == 0 || len(bg) == 0 {
+		return 0
+	}
+
+	remaining := make(map[string]int, len(bg))
+	for _, bi := range bg {
+		remaining[bi]++
+	}
+
+	shared := 0
+	for _, bi := range ag {
+		if remaining[bi] > 0 {
+			shared++
+			remaining[bi]--
+		}
+	}
+
+	longest := len(ag)
+	if len(bg) > longest {
+		longest = len(bg)
+	}
+	return float64(shared) / float64(longest)
+}
+
+func bigrams(s string) []string {
+	r := []rune(s)
+	if len(r) < 2 {
+		return []string{s}
+	}
+	out := make([]string, 0, len(r)-1)
+	for i := 0; i < len(r)-1; i++ {
+		out = append(out, string(r[i:i+2]))
+	}
+	return out
+}
@@ -0,0 +1,200 @@
+package mapping
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sheetFmt/internal/logger"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/option"
+)
+
+func init() {
+	RegisterProvider("gemini", newGeminiProvider)
+}
+
+const geminiDefaultModel = "gemini-2.0-flash-exp"
+const geminiDefaultAPIKeyEnvVar = "GEMINI_API_KEY"
+const geminiDefaultTimeout = 60 * time.Second
+
+// geminiMappingSchema is mappingResponseSchema expressed as a *genai.Schema,
+// the typed form Gemini's SDK requires for ResponseSchema, so the model's
+// output is constrained to it by the API rather than merely asked for in
+// the prompt.
+var geminiMappingSchema = &genai.Schema{
+	Type: genai.TypeObject,
+	Properties: map[string]*genai.Schema{
+		"mappings": {
+			Type: genai.TypeArray,
+			Items: &genai.Schema{
+				Type: genai.TypeObject,
+				Properties: map[string]*genai.Schema{
+					"scanned":    {Type: genai.TypeString},
+					"target":     {Type: genai.TypeString},
+					"confidence": {Type: genai.TypeNumber},
+					"reasoning":  {Type: genai.TypeString},
+				},
+				Required: []string{"scanned", "target", "confidence", "reasoning"},
+			},
+		},
+	},
+	Required: []string{"mappings"},
+}
+
+// geminiProvider is the original AI backend, using Google's Gemini API.
+type geminiProvider struct {
+	client     *genai.Client
+	model      *genai.GenerativeModel
+	timeout    time.Duration
+	maxRetries int
+}
+
+func newGeminiProvider(cfg ProviderConfig) (AIProvider, error) {
+	envVar := cfg.APIKeyEnvVar
+	if envVar == "" {
+		envVar = geminiDefaultAPIKeyEnvVar
+	}
+	apiKey := os.Getenv(envVar)
+	if apiKey == "" {
+		return nil, fmt.Errorf("gemini API key is required (set %s)", envVar)
+	}
+
+	logger.Info("Initializing AI mapper with Gemini API")
+
+	ctx := context.Background()
+	client, err := genai.NewClient(ctx, option.WithAPIKey(apiKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Gemini client: %v", err)
+	}
+
+	modelName := cfg.Model
+	if modelName == "" {
+		modelName = geminiDefaultModel
+	}
+	temperature := cfg.Temperature
+	if temperature == 0 {
+		temperature = 0.1
+	}
+	model := client.GenerativeModel(modelName)
+	model.SetTemperature(float32(temperature))
+	model.ResponseMIMEType = "application/json"
+	model.ResponseSchema = geminiMappingSchema
+
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = geminiDefaultTimeout
+	}
+
+	logger.Info("AI mapper initialized successfully", "model", modelName, "temperature", temperature)
+
+	return &geminiProvider{client: client, model: model, timeout: timeout, maxRetries: cfg.MaxRetries}, nil
+}
+
+// GetGeminiAPIKey gets the API key from environment variable
+func GetGeminiAPIKey() string {
+	apiKey := os.Getenv(geminiDefaultAPIKeyEnvVar)
+	if apiKey == "" {
+		logger.Warn("GEMINI_API_KEY environment variable not set")
+	}
+	return apiKey
+}
+
+func (g *geminiProvider) Name() string { return "gemini" }
+
+// Close cleans up the AI mapper resources
+func (g *geminiProvider) Close() error {
+	if g.client != nil {
+		logger.Debug("Closing AI mapper client")
+		return g.client.Close()
+	}
+	return nil
+}
+
+func (g *geminiProvider) GenerateColumnMappings(ctx context.Context, scannedColumns, targetColumns []string) ([]AIMapping, error) {
+	if len(scannedColumns) == 0 || len(targetColumns) == 0 {
+		return nil, fmt.Errorf("both scanned and target columns must be provided")
+	}
+
+	logger.Info("Generating AI column mappings",
+		"provider", "gemini",
+		"scanned_count", len(scannedColumns),
+		"target_count", len(targetColumns))
+
+	// 50 columns is fine, only chunk if we have 100+ columns
+	if len(scannedColumns) > 100 {
+		logger.Info("Very large request detected, processing in chunks", "total_columns", len(scannedColumns))
+		return chunkAndGenerate(scannedColumns, targetColumns, 50, func(chunk []string) ([]AIMapping, error) {
+			return g.generateSingleBatch(ctx, chunk, targetColumns)
+		})
+	}
+
+	return g.generateSingleBatch(ctx, scannedColumns, targetColumns)
+}
+
+func (g *geminiProvider) generateSingleBatch(ctx context.Context, scannedColumns, targetColumns []string) ([]AIMapping, error) {
+	prompt := buildMappingPrompt(scannedColumns, targetColumns)
+
+	var mappings []AIMapping
+	err := retryWithBackoff(ctx, g.maxRetries, func() error {
+		result, err := g.callAPI(ctx, prompt, targetColumns)
+		if err != nil {
+			return err
+		}
+		mappings = result
+		return nil
+	})
+	return mappings, err
+}
+
+func (g *geminiProvider) callAPI(ctx context.Context, prompt string, targetColumns []string) ([]AIMapping, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.timeout)
+	defer cancel()
+
+	logger.Info("Sending request to Gemini API", "timeout", g.timeout)
+
+	type apiResult struct {
+		resp *genai.GenerateContentResponse
+		err  error
+	}
+
+	resultChan := make(chan apiResult, 1)
+	apiStartTime := time.Now()
+
+	go func() {
+		resp, err := g.model.GenerateContent(ctx, genai.Text(prompt))
+		resultChan <- apiResult{resp: resp, err: err}
+	}()
+
+	select {
+	case result := <-resultChan:
+		if result.err != nil {
+			return nil, fmt.Errorf("failed to generate AI response: %v", result.err)
+		}
+		logger.Info("Received response from Gemini API", "duration", time.Since(apiStartTime))
+		return g.processAPIResponse(result.resp, targetColumns)
+
+	case <-ctx.Done():
+		return nil, fmt.Errorf("API request timed out after %v", g.timeout)
+	}
+}
+
+func (g *geminiProvider) processAPIResponse(resp *genai.GenerateContentResponse, targetColumns []string) ([]AIMapping, error) {
+	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
+		return nil, fmt.Errorf("no response generated from AI")
+	}
+
+	var responseText string
+	for _, part := range resp.Candidates[0].Content.Parts {
+		if textPart, ok := part.(genai.Text); ok {
+			responseText += string(textPart)
+		}
+	}
+
+	mappings, err := parseMappingResponse(responseText, targetColumns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse AI response: %v", err)
+	}
+	return mappings, nil
+}
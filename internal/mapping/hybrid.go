@@ -0,0 +1,498 @@
+package mapping
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"sheetFmt/internal/logger"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// defaultMatchThreshold is the minimum blended score (Jaccard +
+// Levenshtein-ratio + LCS-ratio, stage 2) a scanned/target pair needs to be
+// auto-accepted without calling an AI provider.
+const defaultMatchThreshold = 0.92
+
+// defaultEmbeddingThreshold is the minimum cosine similarity (stage 3) a
+// scanned/target pair needs to be auto-accepted.
+const defaultEmbeddingThreshold = 0.85
+
+// HybridOptions configures NewHybridProvider's pre-LLM deterministic and
+// embedding passes. Zero values fall back to sensible defaults; a zero
+// Embedder simply disables stage 3.
+type HybridOptions struct {
+	// SynonymsPath is where the stage 1 synonym dictionary is loaded from.
+	// Empty uses defaultSynonymsPath.
+	SynonymsPath string
+	// MatchThreshold is the stage 2 deterministic-score floor. Zero uses
+	// defaultMatchThreshold.
+	MatchThreshold float64
+	// Embedder computes stage 3 embeddings. Nil skips straight from stage 2
+	// to the wrapped AI provider.
+	Embedder EmbeddingProvider
+	// EmbeddingThreshold is the stage 3 cosine-similarity floor. Zero uses
+	// defaultEmbeddingThreshold.
+	EmbeddingThreshold float64
+	// Cache persists stage 3 embeddings across runs, keyed by the
+	// embedder's name and a column's normalized form. Nil disables caching.
+	Cache *embeddingCache
+}
+
+// hybridProvider wraps an AIProvider with the cheap, deterministic passes
+// described in NewHybridProvider's doc comment, only forwarding the
+// columns none of those passes could resolve to the wrapped provider.
+type hybridProvider struct {
+	inner              AIProvider
+	synonyms           SynonymDict
+	matchThreshold     float64
+	embedder           EmbeddingProvider
+	embeddingThreshold float64
+	cache              *embeddingCache
+}
+
+// NewHybridProvider wraps inner with a four-stage pre-LLM pipeline so most
+// "easy" columns resolve without ever reaching inner's API:
+//
+//  1. Exact match on the normalized, synonym-substituted column names
+//     (confidence 1.0).
+//  2. A blend of token-set Jaccard, normalized Levenshtein similarity, and
+//     longest-common-subsequence ratio on the normalized names, accepted
+//     above opts.MatchThreshold.
+//  3. Cosine similarity between opts.Embedder embeddings of the remaining
+//     columns, accepted above opts.EmbeddingThreshold, with results cached
+//     in opts.Cache so reruns over similar sheets skip the embedding call
+//     entirely.
+//  4. Whatever's still unresolved goes through inner's own prompt, the same
+//     as if hybridProvider weren't in the picture.
+//
+// Every stage assigns at most one scanned column to a given target column;
+// once a target is claimed, later stages (including inner's LLM call) only
+// ever see the remaining unclaimed targets.
+func NewHybridProvider(inner AIProvider, opts HybridOptions) (AIProvider, error) {
+	synonyms, err := loadSynonymDict(opts.SynonymsPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load synonym dictionary: %v", err)
+	}
+
+	matchThreshold := opts.MatchThreshold
+	if matchThreshold <= 0 {
+		matchThreshold = defaultMatchThreshold
+	}
+	embeddingThreshold := opts.EmbeddingThreshold
+	if embeddingThreshold <= 0 {
+		embeddingThreshold = defaultEmbeddingThreshold
+	}
+
+	return &hybridProvider{
+		inner:              inner,
+		synonyms:           synonyms,
+		matchThreshold:     matchThreshold,
+		embedder:           opts.Embedder,
+		embeddingThreshold: embeddingThreshold,
+		cache:              opts.Cache,
+	}, nil
+}
+
+func (h *hybridProvider) Name() string { return "hybrid+" + h.inner.Name() }
+
+func (h *hybridProvider) Close() error {
+	var firstErr error
+	if h.embedder != nil {
+		if err := h.embedder.Close(); err != nil {
+			firstErr = err
+		}
+	}
+	if h.cache != nil {
+		if err := h.cache.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if err := h.inner.Close(); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	return firstErr
+}
+
+// resolution is one scanned column's outcome from the deterministic or
+// embedding stages, or its absence if none of them could place it.
+type resolution struct {
+	scanned string
+	mapping AIMapping
+}
+
+func (h *hybridProvider) GenerateColumnMappings(ctx context.Context, scannedColumns, targetColumns []string) ([]AIMapping, error) {
+	mappings, remaining, unclaimedTargets, err := h.runPrePass(ctx, scannedColumns, targetColumns)
+	if err != nil {
+		return nil, err
+	}
+	if len(remaining) == 0 {
+		return mappings, nil
+	}
+
+	aiMappings, err := h.inner.GenerateColumnMappings(ctx, remaining, unclaimedTargets)
+	if err != nil {
+		return mappings, err
+	}
+	return append(mappings, aiMappings...), nil
+}
+
+// GenerateColumnMappingsStream runs the same pre-LLM pass as
+// GenerateColumnMappings, reporting each resolution to onProgress
+// immediately since those stages are local and effectively instant, then
+// delegates whatever's left to inner's own streaming call if it supports
+// one (falling back to its plain GenerateColumnMappings otherwise).
+// Without this, wrapping a StreamingAIProvider like the openai backend in
+// a hybridProvider would silently drop the TUI's live progress bar.
+func (h *hybridProvider) GenerateColumnMappingsStream(ctx context.Context, scannedColumns, targetColumns []string, onProgress func(mapping AIMapping)) ([]AIMapping, error) {
+	mappings, remaining, unclaimedTargets, err := h.runPrePass(ctx, scannedColumns, targetColumns)
+	if err != nil {
+		return nil, err
+	}
+	if onProgress != nil {
+		for _, mapping := range mappings {
+			onProgress(mapping)
+		}
+	}
+	if len(remaining) == 0 {
+		return mappings, nil
+	}
+
+	var aiMappings []AIMapping
+	if streaming, ok := h.inner.(StreamingAIProvider); ok {
+		aiMappings, err = streaming.GenerateColumnMappingsStream(ctx, remaining, unclaimedTargets, onProgress)
+	} else {
+		aiMappings, err = h.inner.GenerateColumnMappings(ctx, remaining, unclaimedTargets)
+		if err == nil && onProgress != nil {
+			for _, mapping := range aiMappings {
+				onProgress(mapping)
+			}
+		}
+	}
+	if err != nil {
+		return mappings, err
+	}
+	return append(mappings, aiMappings...), nil
+}
+
+// runPrePass is GenerateColumnMappings and GenerateColumnMappingsStream's
+// shared stage 1-3 pipeline. It returns the mappings those stages resolved
+// on their own, whichever scanned columns are still unresolved, and
+// whichever target columns remain unclaimed for inner's LLM call to pick
+// from.
+func (h *hybridProvider) runPrePass(ctx context.Context, scannedColumns, targetColumns []string) (mappings []AIMapping, remaining []string, unclaimedTargets []string, err error) {
+	if len(scannedColumns) == 0 || len(targetColumns) == 0 {
+		return nil, nil, nil, fmt.Errorf("both scanned and target columns must be provided")
+	}
+
+	claimedTarget := make(map[string]bool, len(targetColumns))
+	var resolved []resolution
+	remaining = append([]string(nil), scannedColumns...)
+
+	remaining = h.resolveStage(remaining, targetColumns, claimedTarget, &resolved,
+		"exact", func(scanned, target string) (float64, bool) {
+			if h.normalizeWithSynonyms(scanned) == h.normalizeWithSynonyms(target) {
+				return 1.0, true
+			}
+			return 0, false
+		})
+
+	remaining = h.resolveStage(remaining, targetColumns, claimedTarget, &resolved,
+		"deterministic", func(scanned, target string) (float64, bool) {
+			score := hybridStageScore(normalizeForHybrid(scanned), normalizeForHybrid(target))
+			return score, score >= h.matchThreshold
+		})
+
+	if h.embedder != nil && len(remaining) > 0 {
+		remaining, err = h.resolveEmbeddingStage(ctx, remaining, targetColumns, claimedTarget, &resolved)
+		if err != nil {
+			logger.Warn("Embedding stage failed, falling back to AI provider for remaining columns", "error", err)
+			err = nil
+		}
+	}
+
+	logger.Info("Hybrid pre-pass resolved columns without an AI call",
+		"resolved", len(resolved), "remaining", len(remaining), "total", len(scannedColumns))
+
+	mappings = make([]AIMapping, 0, len(resolved))
+	for _, r := range resolved {
+		mappings = append(mappings, r.mapping)
+	}
+
+	unclaimedTargets = make([]string, 0, len(targetColumns))
+	for _, target := range targetColumns {
+		if !claimedTarget[target] {
+			unclaimedTargets = append(unclaimedTargets, target)
+		}
+	}
+
+	return mappings, remaining, unclaimedTargets, nil
+}
+
+// resolveStage runs score against every (scanned, target) pair still in
+// candidates, keeping the best-scoring match per scanned column that
+// clears its accept condition, then greedily assigns highest-score-first
+// so no target is claimed twice. It returns whichever scanned columns
+// remain unresolved, same pattern SuggestMappings uses for its own
+// one-to-one assignment.
+func (h *hybridProvider) resolveStage(candidates, targets []string, claimedTarget map[string]bool, resolved *[]resolution, stageName string, score func(scanned, target string) (float64, bool)) []string {
+	type match struct {
+		scanned string
+		target  string
+		score   float64
+	}
+
+	var matches []match
+	for _, scanned := range candidates {
+		bestTarget, bestScore := "", 0.0
+		found := false
+		for _, target := range targets {
+			if claimedTarget[target] {
+				continue
+			}
+			s, ok := score(scanned, target)
+			if ok && s > bestScore {
+				bestTarget, bestScore, found = target, s, true
+			}
+		}
+		if found {
+			matches = append(matches, match{scanned: scanned, target: bestTarget, score: bestScore})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+
+	resolvedScanned := make(map[string]bool, len(matches))
+	for _, m := range matches {
+		if claimedTarget[m.target] {
+			continue
+		}
+		claimedTarget[m.target] = true
+		resolvedScanned[m.scanned] = true
+		logger.Debug("Hybrid stage resolved column", "stage", stageName, "scanned", m.scanned, "target", m.target, "score", m.score)
+		*resolved = append(*resolved, resolution{
+			scanned: m.scanned,
+			mapping: AIMapping{ScannedColumn: m.scanned, TargetColumn: m.target, Confidence: m.score},
+		})
+	}
+
+	var remaining []string
+	for _, scanned := range candidates {
+		if !resolvedScanned[scanned] {
+			remaining = append(remaining, scanned)
+		}
+	}
+	return remaining
+}
+
+// resolveEmbeddingStage is resolveStage's stage 3 counterpart: computing a
+// score requires a batched, cached, possibly-failing API call up front
+// rather than a pure function, so it can't share resolveStage's signature.
+func (h *hybridProvider) resolveEmbeddingStage(ctx context.Context, candidates, targets []string, claimedTarget map[string]bool, resolved *[]resolution) ([]string, error) {
+	unclaimedTargets := make([]string, 0, len(targets))
+	for _, target := range targets {
+		if !claimedTarget[target] {
+			unclaimedTargets = append(unclaimedTargets, target)
+		}
+	}
+	if len(unclaimedTargets) == 0 {
+		return candidates, nil
+	}
+
+	scannedVecs, err := h.embeddingsFor(ctx, candidates)
+	if err != nil {
+		return candidates, err
+	}
+	targetVecs, err := h.embeddingsFor(ctx, unclaimedTargets)
+	if err != nil {
+		return candidates, err
+	}
+
+	type match struct {
+		scanned string
+		target  string
+		score   float64
+	}
+	var matches []match
+	for _, scanned := range candidates {
+		bestTarget, bestScore := "", 0.0
+		found := false
+		for _, target := range unclaimedTargets {
+			score := cosineSimilarity(scannedVecs[scanned], targetVecs[target])
+			if score >= h.embeddingThreshold && score > bestScore {
+				bestTarget, bestScore, found = target, score, true
+			}
+		}
+		if found {
+			matches = append(matches, match{scanned: scanned, target: bestTarget, score: bestScore})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+
+	resolvedScanned := make(map[string]bool, len(matches))
+	for _, m := range matches {
+		if claimedTarget[m.target] {
+			continue
+		}
+		claimedTarget[m.target] = true
+		resolvedScanned[m.scanned] = true
+		logger.Debug("Hybrid stage resolved column", "stage", "embedding", "scanned", m.scanned, "target", m.target, "score", m.score)
+		*resolved = append(*resolved, resolution{
+			scanned: m.scanned,
+			mapping: AIMapping{ScannedColumn: m.scanned, TargetColumn: m.target, Confidence: m.score},
+		})
+	}
+
+	var remaining []string
+	for _, scanned := range candidates {
+		if !resolvedScanned[scanned] {
+			remaining = append(remaining, scanned)
+		}
+	}
+	return remaining, nil
+}
+
+// embeddingsFor returns each of columns' embedding vector, keyed by its
+// original (non-normalized) string, serving whatever it can from h.cache
+// and only calling h.embedder for the columns that missed.
+func (h *hybridProvider) embeddingsFor(ctx context.Context, columns []string) (map[string][]float64, error) {
+	vecs := make(map[string][]float64, len(columns))
+	var misses []string
+	normalized := make(map[string]string, len(columns))
+
+	for _, col := range columns {
+		normCol := normalizeForHybrid(col)
+		normalized[col] = normCol
+		if h.cache != nil {
+			if vec, ok := h.cache.get(h.embedder.Name(), normCol); ok {
+				vecs[col] = vec
+				continue
+			}
+		}
+		misses = append(misses, col)
+	}
+
+	if len(misses) == 0 {
+		return vecs, nil
+	}
+
+	missNorms := make([]string, len(misses))
+	for i, col := range misses {
+		missNorms[i] = normalized[col]
+	}
+
+	computed, err := h.embedder.Embed(ctx, missNorms)
+	if err != nil {
+		return nil, err
+	}
+	if len(computed) != len(misses) {
+		return nil, fmt.Errorf("embedding provider returned %d vectors for %d inputs", len(computed), len(misses))
+	}
+
+	for i, col := range misses {
+		vecs[col] = computed[i]
+		if h.cache != nil {
+			if err := h.cache.put(h.embedder.Name(), normalized[col], computed[i]); err != nil {
+				logger.Warn("Failed to cache embedding", "column", col, "error", err)
+			}
+		}
+	}
+	return vecs, nil
+}
+
+// normalizeWithSynonyms normalizes name and substitutes it for its
+// canonical form if it's a known alias, so stage 1's exact match catches
+// e.g. "Cust ID" against a target literally named "Customer ID".
+func (h *hybridProvider) normalizeWithSynonyms(name string) string {
+	normalized := normalizeForHybrid(name)
+	if canonical, ok := h.synonyms[normalized]; ok {
+		return canonical
+	}
+	return normalized
+}
+
+var nonAlnumSpace = regexp.MustCompile(`[^a-z0-9\s]+`)
+var multiSpace = regexp.MustCompile(`\s+`)
+
+// normalizeForHybrid lowercases name, strips diacritics (so "Número" and
+// "Numero" compare equal), strips anything that isn't a letter, digit, or
+// space, and collapses whitespace. Stricter than normalizeForSuggest,
+// which strips punctuation but keeps accents, because stage 1's
+// exact-match test needs to treat accents as noise too.
+func normalizeForHybrid(name string) string {
+	lower := strings.ToLower(strings.TrimSpace(name))
+
+	deaccented, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), lower)
+	if err != nil {
+		deaccented = lower
+	}
+
+	stripped := nonAlnumSpace.ReplaceAllString(deaccented, " ")
+	return strings.TrimSpace(multiSpace.ReplaceAllString(stripped, " "))
+}
+
+// lcsRatio is the longest common subsequence of a and b, expressed as a
+// fraction of the longer string's length.
+func lcsRatio(a, b string) float64 {
+	ar, br := []rune(a), []rune(b)
+	la, lb := len(ar), len(br)
+	if la == 0 || lb == 0 {
+		if la == 0 && lb == 0 {
+			return 1
+		}
+		return 0
+	}
+
+	prev := make([]int, lb+1)
+	curr := make([]int, lb+1)
+	for i := 1; i <= la; i++ {
+		for j := 1; j <= lb; j++ {
+			if ar[i-1] == br[j-1] {
+				curr[j] = prev[j-1] + 1
+			} else if prev[j] >= curr[j-1] {
+				curr[j] = prev[j]
+			} else {
+				curr[j] = curr[j-1]
+			}
+		}
+		prev, curr = curr, prev
+	}
+
+	maxLen := la
+	if lb > maxLen {
+		maxLen = lb
+	}
+	return float64(prev[lb]) / float64(maxLen)
+}
+
+// hybridStageScore blends token-set Jaccard similarity, normalized
+// Levenshtein similarity, and LCS ratio in equal thirds. It's a separate,
+// stricter blend from suggestScore's 0.5/0.5 Jaccard/Levenshtein used by
+// SuggestMappings: this one feeds an auto-accept gate with no human
+// review, so it leans on a third signal before trusting a match.
+func hybridStageScore(a, b string) float64 {
+	if a == "" && b == "" {
+		return 1
+	}
+	if a == "" || b == "" {
+		return 0
+	}
+
+	jaccard := tokenSetJaccard(a, b)
+
+	maxLen := len(a)
+	if len(b) > maxLen {
+		maxLen = len(b)
+	}
+	levSim := 1 - float64(damerauLevenshtein(a, b))/float64(maxLen)
+
+	lcs := lcsRatio(a, b)
+
+	return (jaccard + levSim + lcs) / 3
+}
@@ -0,0 +1,56 @@
+package mapping
+
+import "testing"
+
+func TestLCSRatio(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want float64
+	}{
+		{"", "", 1},
+		{"abc", "", 0},
+		{"", "abc", 0},
+		{"abc", "abc", 1},
+		{"abc", "axc", 2.0 / 3.0},
+		{"abcde", "ace", 3.0 / 5.0},
+	}
+	for _, c := range cases {
+		if got := lcsRatio(c.a, c.b); got != c.want {
+			t.Errorf("lcsRatio(%q, %q) = %v, want %v", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestHybridStageScoreIdenticalIsOne(t *testing.T) {
+	if got := hybridStageScore("customer id", "customer id"); got != 1 {
+		t.Errorf("hybridStageScore on identical strings = %v, want 1", got)
+	}
+}
+
+func TestHybridStageScoreEmptyVsNonEmptyIsZero(t *testing.T) {
+	if got := hybridStageScore("customer id", ""); got != 0 {
+		t.Errorf("hybridStageScore(%q, %q) = %v, want 0", "customer id", "", got)
+	}
+}
+
+func TestHybridStageScoreBothEmptyIsOne(t *testing.T) {
+	if got := hybridStageScore("", ""); got != 1 {
+		t.Errorf("hybridStageScore(\"\", \"\") = %v, want 1", got)
+	}
+}
+
+func TestHybridStageScoreBlendsThreeSignalsEqually(t *testing.T) {
+	a, b := "customer id", "customer identifier"
+	jaccard := tokenSetJaccard(a, b)
+	maxLen := len(a)
+	if len(b) > maxLen {
+		maxLen = len(b)
+	}
+	levSim := 1 - float64(damerauLevenshtein(a, b))/float64(maxLen)
+	lcs := lcsRatio(a, b)
+	want := (jaccard + levSim + lcs) / 3
+
+	if got := hybridStageScore(a, b); got != want {
+		t.Errorf("hybridStageScore(%q, %q) = %v, want %v", a, b, got, want)
+	}
+}
@@ -0,0 +1,169 @@
+package mapping
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"sheetFmt/internal/logger"
+)
+
+func init() {
+	RegisterProvider("ollama", newOllamaProvider)
+}
+
+const ollamaDefaultHost = "http://localhost:11434"
+const ollamaDefaultModel = "llama3.1"
+const ollamaDefaultTimeout = 120 * time.Second
+const ollamaDefaultTemperature = 0.1
+
+// ollamaProvider talks to a local Ollama server. Unlike the other
+// providers it needs no API key, just a reachable endpoint, so it works
+// without any cloud credentials configured; cfg's APIKeyEnvVar is ignored
+// for that reason.
+type ollamaProvider struct {
+	host        string
+	model       string
+	temperature float64
+	maxRetries  int
+	client      *http.Client
+}
+
+func newOllamaProvider(cfg ProviderConfig) (AIProvider, error) {
+	host := cfg.Endpoint
+	if host == "" {
+		host = os.Getenv("OLLAMA_HOST")
+	}
+	if host == "" {
+		host = ollamaDefaultHost
+	}
+
+	model := cfg.Model
+	if model == "" {
+		model = os.Getenv("OLLAMA_MODEL")
+	}
+	if model == "" {
+		model = ollamaDefaultModel
+	}
+
+	temperature := cfg.Temperature
+	if temperature == 0 {
+		temperature = ollamaDefaultTemperature
+	}
+
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = ollamaDefaultTimeout
+	}
+
+	return &ollamaProvider{
+		host:        host,
+		model:       model,
+		temperature: temperature,
+		maxRetries:  cfg.MaxRetries,
+		client:      &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func (o *ollamaProvider) Name() string { return "ollama" }
+
+func (o *ollamaProvider) Close() error { return nil }
+
+type ollamaGenerateRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+	Stream bool   `json:"stream"`
+	// Format takes a JSON Schema document (mappingResponseSchema), not just
+	// the older "json" mode string, so Ollama constrains decoding to the
+	// schema's shape instead of merely valid JSON.
+	Format  map[string]any        `json:"format"`
+	Options ollamaGenerateOptions `json:"options"`
+}
+
+type ollamaGenerateOptions struct {
+	Temperature float64 `json:"temperature"`
+}
+
+type ollamaGenerateResponse struct {
+	Response string `json:"response"`
+	Done     bool   `json:"done"`
+}
+
+func (o *ollamaProvider) GenerateColumnMappings(ctx context.Context, scannedColumns, targetColumns []string) ([]AIMapping, error) {
+	if len(scannedColumns) == 0 || len(targetColumns) == 0 {
+		return nil, fmt.Errorf("both scanned and target columns must be provided")
+	}
+
+	if len(scannedColumns) > 100 {
+		return chunkAndGenerate(scannedColumns, targetColumns, 50, func(chunk []string) ([]AIMapping, error) {
+			return o.generateSingleBatch(ctx, chunk, targetColumns)
+		})
+	}
+
+	return o.generateSingleBatch(ctx, scannedColumns, targetColumns)
+}
+
+func (o *ollamaProvider) generateSingleBatch(ctx context.Context, scannedColumns, targetColumns []string) ([]AIMapping, error) {
+	prompt := buildMappingPrompt(scannedColumns, targetColumns)
+
+	var mappings []AIMapping
+	err := retryWithBackoff(ctx, o.maxRetries, func() error {
+		result, err := o.callAPI(ctx, prompt, targetColumns)
+		if err != nil {
+			return err
+		}
+		mappings = result
+		return nil
+	})
+	return mappings, err
+}
+
+func (o *ollamaProvider) callAPI(ctx context.Context, prompt string, targetColumns []string) ([]AIMapping, error) {
+	reqBody := ollamaGenerateRequest{
+		Model:  o.model,
+		Prompt: prompt,
+		Stream: false,
+		Format: mappingResponseSchema,
+		Options: ollamaGenerateOptions{
+			Temperature: o.temperature,
+		},
+	}
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode Ollama request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.host+"/api/generate", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build Ollama request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	logger.Info("Sending request to Ollama", "host", o.host, "model", o.model)
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Ollama request failed (is `ollama serve` running?): %v", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Ollama response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Ollama returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var parsed ollamaGenerateResponse
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode Ollama response: %v", err)
+	}
+
+	return parseMappingResponse(parsed.Response, targetColumns)
+}
@@ -0,0 +1,245 @@
+package mapping
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"sheetFmt/internal/logger"
+)
+
+func init() {
+	RegisterProvider("openai", newOpenAIProvider)
+}
+
+const openAIDefaultModel = "gpt-4o-mini"
+const openAIDefaultBaseURL = "https://api.openai.com/v1"
+const openAIDefaultAPIKeyEnvVar = "OPENAI_API_KEY"
+const openAIDefaultTimeout = 90 * time.Second
+const openAIDefaultTemperature = 0.1
+
+// openAIProvider talks to the OpenAI chat-completions endpoint, requesting
+// the response as server-sent events read line by line with the same
+// "data: " framing the zed OpenAI client parses. The deltas are
+// accumulated and decoded once the stream completes, so onProgress sees
+// each mapping only after the whole response has arrived.
+type openAIProvider struct {
+	apiKey      string
+	baseURL     string
+	model       string
+	temperature float64
+	maxRetries  int
+	client      *http.Client
+}
+
+func newOpenAIProvider(cfg ProviderConfig) (AIProvider, error) {
+	envVar := cfg.APIKeyEnvVar
+	if envVar == "" {
+		envVar = openAIDefaultAPIKeyEnvVar
+	}
+	apiKey := os.Getenv(envVar)
+	if apiKey == "" {
+		return nil, fmt.Errorf("openai API key is required (set %s)", envVar)
+	}
+
+	baseURL := cfg.Endpoint
+	if baseURL == "" {
+		baseURL = openAIDefaultBaseURL
+	}
+
+	model := cfg.Model
+	if model == "" {
+		model = openAIDefaultModel
+	}
+
+	temperature := cfg.Temperature
+	if temperature == 0 {
+		temperature = openAIDefaultTemperature
+	}
+
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = openAIDefaultTimeout
+	}
+
+	return &openAIProvider{
+		apiKey:      apiKey,
+		baseURL:     baseURL,
+		model:       model,
+		temperature: temperature,
+		maxRetries:  cfg.MaxRetries,
+		client:      &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func (o *openAIProvider) Name() string { return "openai" }
+
+func (o *openAIProvider) Close() error { return nil }
+
+func (o *openAIProvider) GenerateColumnMappings(ctx context.Context, scannedColumns, targetColumns []string) ([]AIMapping, error) {
+	return o.GenerateColumnMappingsStream(ctx, scannedColumns, targetColumns, nil)
+}
+
+func (o *openAIProvider) GenerateColumnMappingsStream(ctx context.Context, scannedColumns, targetColumns []string, onProgress func(mapping AIMapping)) ([]AIMapping, error) {
+	if len(scannedColumns) == 0 || len(targetColumns) == 0 {
+		return nil, fmt.Errorf("both scanned and target columns must be provided")
+	}
+
+	if len(scannedColumns) > 100 {
+		return chunkAndGenerate(scannedColumns, targetColumns, 50, func(chunk []string) ([]AIMapping, error) {
+			return o.retryingStreamBatch(ctx, chunk, targetColumns, onProgress)
+		})
+	}
+
+	return o.retryingStreamBatch(ctx, scannedColumns, targetColumns, onProgress)
+}
+
+// retryingStreamBatch wraps streamSingleBatch in retryWithBackoff. A retried
+// attempt restarts the stream from scratch. streamSingleBatch reports to
+// onProgress only after the whole response has decoded, so a failed attempt
+// reports nothing and a retry does not repeat earlier progress.
+func (o *openAIProvider) retryingStreamBatch(ctx context.Context, scannedColumns, targetColumns []string, onProgress func(mapping AIMapping)) ([]AIMapping, error) {
+	var mappings []AIMapping
+	err := retryWithBackoff(ctx, o.maxRetries, func() error {
+		result, err := o.streamSingleBatch(ctx, scannedColumns, targetColumns, onProgress)
+		if err != nil {
+			return err
+		}
+		mappings = result
+		return nil
+	})
+	return mappings, err
+}
+
+type openAIChatRequest struct {
+	Model          string               `json:"model"`
+	Messages       []openAIChatMessage  `json:"messages"`
+	Temperature    float64              `json:"temperature"`
+	Stream         bool                 `json:"stream"`
+	ResponseFormat openAIResponseFormat `json:"response_format"`
+}
+
+type openAIChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// openAIResponseFormat requests OpenAI's schema-constrained decoding;
+// "strict" rejects any completion that doesn't conform to Schema instead
+// of merely nudging the model toward it.
+type openAIResponseFormat struct {
+	Type       string           `json:"type"`
+	JSONSchema openAIJSONSchema `json:"json_schema"`
+}
+
+type openAIJSONSchema struct {
+	Name   string         `json:"name"`
+	Strict bool           `json:"strict"`
+	Schema map[string]any `json:"schema"`
+}
+
+type openAIChatChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+		FinishReason *string `json:"finish_reason"`
+	} `json:"choices"`
+}
+
+func (o *openAIProvider) streamSingleBatch(ctx context.Context, scannedColumns, targetColumns []string, onProgress func(mapping AIMapping)) ([]AIMapping, error) {
+	prompt := buildMappingPrompt(scannedColumns, targetColumns)
+
+	reqBody := openAIChatRequest{
+		Model: o.model,
+		Messages: []openAIChatMessage{
+			{Role: "user", Content: prompt},
+		},
+		Temperature: o.temperature,
+		Stream:      true,
+		ResponseFormat: openAIResponseFormat{
+			Type: "json_schema",
+			JSONSchema: openAIJSONSchema{
+				Name:   "column_mappings",
+				Strict: true,
+				Schema: mappingResponseSchema,
+			},
+		},
+	}
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode OpenAI request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build OpenAI request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+o.apiKey)
+
+	logger.Info("Sending request to OpenAI API", "model", o.model)
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("OpenAI request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OpenAI API returned status %d", resp.StatusCode)
+	}
+
+	// Schema-constrained output is still streamed as raw JSON tokens, so
+	// individual deltas aren't parseable on their own the way a
+	// pipe-delimited line was; accumulate the whole object and decode it
+	// once the stream completes.
+	var content strings.Builder
+
+	scanner := bufio.NewScanner(resp.Body)
+	// Chat completion chunks can exceed the scanner's default 64KiB line
+	// buffer under heavy streaming; give it more room.
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+
+		payload := strings.TrimPrefix(line, "data: ")
+		if payload == "[DONE]" {
+			break
+		}
+
+		var chunk openAIChatChunk
+		if err := json.Unmarshal([]byte(payload), &chunk); err != nil {
+			logger.Debug("Skipping unparseable OpenAI stream chunk", "error", err)
+			continue
+		}
+		for _, choice := range chunk.Choices {
+			content.WriteString(choice.Delta.Content)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading OpenAI stream: %v", err)
+	}
+
+	mappings, err := parseMappingResponse(content.String(), targetColumns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse AI response: %v", err)
+	}
+	if onProgress != nil {
+		for _, mapping := range mappings {
+			onProgress(mapping)
+		}
+	}
+
+	return mappings, nil
+}
@@ -0,0 +1,329 @@
+package mapping
+
+import (
+	"fmt"
+	"time"
+)
+
+// OpKind identifies what kind of mutation an Op recorded, used by the
+// :history overlay and for telling ops apart without a type switch.
+type OpKind int
+
+const (
+	OpMap OpKind = iota
+	OpIgnore
+	OpUnmap
+	OpAcceptAI
+	OpBulkAI
+	OpBulkAccept
+)
+
+func (k OpKind) String() string {
+	switch k {
+	case OpMap:
+		return "map"
+	case OpIgnore:
+		return "ignore"
+	case OpUnmap:
+		return "unmap"
+	case OpAcceptAI:
+		return "accept-ai"
+	case OpBulkAI:
+		return "bulk-ai"
+	case OpBulkAccept:
+		return "bulk-accept"
+	default:
+		return "unknown"
+	}
+}
+
+// Op is a single undoable mutation to a model's mappings, ignored, or
+// aiSuggestions maps. Every Op captures whatever it overwrote at
+// construction time, so apply/unapply are plain, symmetric replays
+// instead of having to recompute state from scratch.
+type Op interface {
+	Kind() OpKind
+	Time() time.Time
+	Summary() string
+	apply(m *model)
+	unapply(m *model)
+}
+
+type baseOp struct {
+	kind OpKind
+	at   time.Time
+}
+
+func (b baseOp) Kind() OpKind    { return b.kind }
+func (b baseOp) Time() time.Time { return b.at }
+
+// priorState snapshots whatever a scanned column's mapping/ignore/AI
+// state was immediately before an Op touched it, so unapply can restore
+// it exactly.
+type priorState struct {
+	mapping       string
+	hadMapping    bool
+	ignored       bool
+	aiSuggestion  string
+	hadAISuggest  bool
+	aiConfidence  float64
+	hadConfidence bool
+}
+
+func capturePriorState(m *model, scanned string) priorState {
+	mapping, hadMapping := m.mappings[scanned]
+	aiSuggestion, hadAISuggest := m.aiSuggestions[scanned]
+	aiConfidence, hadConfidence := m.aiConfidence[scanned]
+	return priorState{
+		mapping:       mapping,
+		hadMapping:    hadMapping,
+		ignored:       m.ignored[scanned],
+		aiSuggestion:  aiSuggestion,
+		hadAISuggest:  hadAISuggest,
+		aiConfidence:  aiConfidence,
+		hadConfidence: hadConfidence,
+	}
+}
+
+func (p priorState) restore(m *model, scanned string) {
+	if p.hadMapping {
+		m.mappings[scanned] = p.mapping
+	} else {
+		delete(m.mappings, scanned)
+	}
+	if p.ignored {
+		m.ignored[scanned] = true
+	} else {
+		delete(m.ignored, scanned)
+	}
+	if p.hadAISuggest {
+		m.aiSuggestions[scanned] = p.aiSuggestion
+	} else {
+		delete(m.aiSuggestions, scanned)
+	}
+	if p.hadConfidence {
+		m.aiConfidence[scanned] = p.aiConfidence
+	} else {
+		delete(m.aiConfidence, scanned)
+	}
+}
+
+// MapOp records a manual confirmation of scanned -> target.
+type MapOp struct {
+	baseOp
+	scanned string
+	target  string
+	prior   priorState
+}
+
+func newMapOp(m *model, scanned, target string) *MapOp {
+	return &MapOp{baseOp: baseOp{kind: OpMap, at: opNow()}, scanned: scanned, target: target, prior: capturePriorState(m, scanned)}
+}
+
+func (o *MapOp) Summary() string { return fmt.Sprintf("map %q -> %q", o.scanned, o.target) }
+
+func (o *MapOp) apply(m *model) {
+	m.mappings[o.scanned] = o.target
+	delete(m.ignored, o.scanned)
+	delete(m.aiSuggestions, o.scanned)
+	delete(m.aiConfidence, o.scanned)
+}
+
+func (o *MapOp) unapply(m *model) { o.prior.restore(m, o.scanned) }
+
+// IgnoreOp records marking a scanned column as ignored.
+type IgnoreOp struct {
+	baseOp
+	scanned string
+	prior   priorState
+}
+
+func newIgnoreOp(m *model, scanned string) *IgnoreOp {
+	return &IgnoreOp{baseOp: baseOp{kind: OpIgnore, at: opNow()}, scanned: scanned, prior: capturePriorState(m, scanned)}
+}
+
+func (o *IgnoreOp) Summary() string { return fmt.Sprintf("ignore %q", o.scanned) }
+
+func (o *IgnoreOp) apply(m *model) {
+	m.ignored[o.scanned] = true
+	delete(m.mappings, o.scanned)
+	delete(m.aiSuggestions, o.scanned)
+	delete(m.aiConfidence, o.scanned)
+}
+
+func (o *IgnoreOp) unapply(m *model) { o.prior.restore(m, o.scanned) }
+
+// UnmapOp records clearing a scanned column back to unmapped, e.g.
+// toggling "ignore" off.
+type UnmapOp struct {
+	baseOp
+	scanned string
+	prior   priorState
+}
+
+func newUnmapOp(m *model, scanned string) *UnmapOp {
+	return &UnmapOp{baseOp: baseOp{kind: OpUnmap, at: opNow()}, scanned: scanned, prior: capturePriorState(m, scanned)}
+}
+
+func (o *UnmapOp) Summary() string { return fmt.Sprintf("unmap %q", o.scanned) }
+
+func (o *UnmapOp) apply(m *model) {
+	delete(m.mappings, o.scanned)
+	delete(m.ignored, o.scanned)
+}
+
+func (o *UnmapOp) unapply(m *model) { o.prior.restore(m, o.scanned) }
+
+// AcceptAIOp records confirming a scanned column's existing AI
+// suggestion as-is, kept distinct from MapOp so the :history overlay can
+// tell a manual choice apart from an accepted suggestion.
+type AcceptAIOp struct {
+	baseOp
+	scanned string
+	target  string
+	prior   priorState
+}
+
+func newAcceptAIOp(m *model, scanned, target string) *AcceptAIOp {
+	return &AcceptAIOp{baseOp: baseOp{kind: OpAcceptAI, at: opNow()}, scanned: scanned, target: target, prior: capturePriorState(m, scanned)}
+}
+
+func (o *AcceptAIOp) Summary() string { return fmt.Sprintf("accept AI %q -> %q", o.scanned, o.target) }
+
+func (o *AcceptAIOp) apply(m *model) {
+	m.mappings[o.scanned] = o.target
+	delete(m.ignored, o.scanned)
+	delete(m.aiSuggestions, o.scanned)
+	delete(m.aiConfidence, o.scanned)
+}
+
+func (o *AcceptAIOp) unapply(m *model) { o.prior.restore(m, o.scanned) }
+
+// BulkAIOp records an entire batch of AI suggestions landing at once, so
+// a single undo clears the whole batch rather than requiring one undo
+// per suggestion.
+type BulkAIOp struct {
+	baseOp
+	added      map[string]string  // scanned -> target, newly suggested
+	confidence map[string]float64 // scanned -> confidence, parallel to added
+	prior      map[string]priorState
+}
+
+func newBulkAIOp(m *model, added map[string]string, confidence map[string]float64) *BulkAIOp {
+	prior := make(map[string]priorState, len(added))
+	for scanned := range added {
+		prior[scanned] = capturePriorState(m, scanned)
+	}
+	return &BulkAIOp{baseOp: baseOp{kind: OpBulkAI, at: opNow()}, added: added, confidence: confidence, prior: prior}
+}
+
+func (o *BulkAIOp) Summary() string { return fmt.Sprintf("%d AI suggestions", len(o.added)) }
+
+func (o *BulkAIOp) apply(m *model) {
+	for scanned, target := range o.added {
+		m.aiSuggestions[scanned] = target
+		m.aiConfidence[scanned] = o.confidence[scanned]
+	}
+}
+
+func (o *BulkAIOp) unapply(m *model) {
+	for scanned := range o.added {
+		o.prior[scanned].restore(m, scanned)
+	}
+}
+
+// BulkAcceptOp records committing a batch of reviewed AI suggestions into
+// confirmed mappings at once, from the :review overlay's Enter action, so
+// a single undo reopens the whole batch for review rather than requiring
+// one undo per accepted column.
+type BulkAcceptOp struct {
+	baseOp
+	accepted map[string]string // scanned -> target, newly confirmed
+	prior    map[string]priorState
+}
+
+func newBulkAcceptOp(m *model, accepted map[string]string) *BulkAcceptOp {
+	prior := make(map[string]priorState, len(accepted))
+	for scanned := range accepted {
+		prior[scanned] = capturePriorState(m, scanned)
+	}
+	return &BulkAcceptOp{baseOp: baseOp{kind: OpBulkAccept, at: opNow()}, accepted: accepted, prior: prior}
+}
+
+func (o *BulkAcceptOp) Summary() string {
+	return fmt.Sprintf("accepted %d AI suggestions", len(o.accepted))
+}
+
+func (o *BulkAcceptOp) apply(m *model) {
+	for scanned, target := range o.accepted {
+		m.mappings[scanned] = target
+		delete(m.ignored, scanned)
+		delete(m.aiSuggestions, scanned)
+		delete(m.aiConfidence, scanned)
+	}
+}
+
+func (o *BulkAcceptOp) unapply(m *model) {
+	for scanned := range o.accepted {
+		o.prior[scanned].restore(m, scanned)
+	}
+}
+
+// opHistory is a bounded ring of applied Ops with a cursor separating
+// the undone tail (ops[cursor:]) from the applied prefix (ops[:cursor]),
+// the same shape as a text editor's undo stack.
+type opHistory struct {
+	ops    []Op
+	cursor int
+	cap    int
+}
+
+func newOpHistory(capacity int) opHistory {
+	return opHistory{cap: capacity}
+}
+
+// record applies op to m and pushes it onto the history, discarding any
+// redoable tail left over from a previous undo.
+func (h *opHistory) record(m *model, op Op) {
+	op.apply(m)
+
+	h.ops = append(h.ops[:h.cursor], op)
+	if len(h.ops) > h.cap {
+		h.ops = h.ops[len(h.ops)-h.cap:]
+	}
+	h.cursor = len(h.ops)
+}
+
+func (h *opHistory) undo(m *model) bool {
+	if h.cursor == 0 {
+		return false
+	}
+	h.cursor--
+	h.ops[h.cursor].unapply(m)
+	return true
+}
+
+func (h *opHistory) redo(m *model) bool {
+	if h.cursor >= len(h.ops) {
+		return false
+	}
+	h.ops[h.cursor].apply(m)
+	h.cursor++
+	return true
+}
+
+// recent returns up to n of the most recently applied ops, most recent
+// first, for the :history overlay.
+func (h *opHistory) recent(n int) []Op {
+	applied := h.ops[:h.cursor]
+	if n > len(applied) {
+		n = len(applied)
+	}
+	out := make([]Op, n)
+	for i := 0; i < n; i++ {
+		out[i] = applied[len(applied)-1-i]
+	}
+	return out
+}
+
+func opNow() time.Time { return time.Now() }
@@ -0,0 +1,357 @@
+package mapping
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"sheetFmt/internal/logger"
+)
+
+// AIMapping represents an AI-suggested mapping with confidence
+type AIMapping struct {
+	ScannedColumn string  `json:"scanned_column"`
+	TargetColumn  string  `json:"target_column"`
+	Confidence    float64 `json:"confidence"`
+	// Reasoning is the model's short explanation for the mapping, carried
+	// through from the structured response so it can be surfaced in logs.
+	// It is not shown in the TUI itself.
+	Reasoning string `json:"reasoning,omitempty"`
+}
+
+// AIProvider is implemented by every AI backend sheetFmt can ask for
+// column mapping suggestions. Providers are free to call whatever remote
+// (or local) API they like internally, but all speak the same
+// scanned-columns-in, mappings-out contract, and all expect the model to
+// answer with the JSON object described by mappingResponseSchema, parsed
+// by parseMappingResponse.
+type AIProvider interface {
+	// Name identifies the provider for logging and the TUI's
+	// provider-picker overlay, e.g. "gemini", "openai".
+	Name() string
+	GenerateColumnMappings(ctx context.Context, scannedColumns, targetColumns []string) ([]AIMapping, error)
+	Close() error
+}
+
+// StreamingAIProvider is implemented by providers that can report
+// incremental progress (e.g. parsing an SSE stream line-by-line). The TUI
+// uses this to show a real progress bar, land suggestions as they arrive,
+// and keep whatever landed if the request is cancelled mid-stream.
+type StreamingAIProvider interface {
+	AIProvider
+	// GenerateColumnMappingsStream behaves like GenerateColumnMappings but
+	// invokes onProgress with each mapping as soon as it's parsed from the
+	// stream, in addition to returning the full batch at the end.
+	GenerateColumnMappingsStream(ctx context.Context, scannedColumns, targetColumns []string, onProgress func(mapping AIMapping)) ([]AIMapping, error)
+}
+
+// DefaultProviderName is used when no provider has been explicitly
+// configured or picked in the TUI.
+const DefaultProviderName = "gemini"
+
+// ProviderConfig carries the config.AIConfig settings a provider factory
+// needs, translated from config.Config by the CLI the same way
+// config.UIConfig becomes mapping.UIConfig. A zero-value ProviderConfig
+// (NewProvider's callers that don't read config.toml) leaves every
+// provider on its own built-in defaults.
+type ProviderConfig struct {
+	// Model overrides the provider's default model name when non-empty.
+	Model string
+	// Temperature is passed to providers that support it.
+	Temperature float64
+	// Endpoint overrides the provider's default API base URL or host when
+	// non-empty.
+	Endpoint string
+	// APIKeyEnvVar overrides the environment variable a provider reads its
+	// API key from when non-empty.
+	APIKeyEnvVar string
+	// Timeout bounds a single request to the provider's API. Zero means
+	// use the provider's built-in default.
+	Timeout time.Duration
+	// MaxRetries is how many additional attempts retryWithBackoff makes
+	// after a failed request.
+	MaxRetries int
+
+	// DisableHybridMapping skips NewProvider's deterministic/embedding
+	// pre-pass entirely, sending every column straight to the provider's
+	// own LLM prompt. Mainly useful for debugging a suspect pre-pass match.
+	DisableHybridMapping bool
+	// SynonymsPath overrides where the hybrid pre-pass loads its synonym
+	// dictionary from. Empty uses defaultSynonymsPath.
+	SynonymsPath string
+	// MatchThreshold overrides the hybrid pre-pass's deterministic-score
+	// floor. Zero uses defaultMatchThreshold.
+	MatchThreshold float64
+	// EmbeddingProvider names the registered EmbeddingProvider the hybrid
+	// pre-pass's stage 3 should use (e.g. "gemini", "openai", "ollama").
+	// Empty disables stage 3 entirely, going straight from the
+	// deterministic stage to the LLM for whatever it didn't resolve.
+	EmbeddingProvider string
+	// EmbeddingThreshold overrides the hybrid pre-pass's cosine-similarity
+	// floor. Zero uses defaultEmbeddingThreshold.
+	EmbeddingThreshold float64
+	// EmbeddingCachePath overrides where stage 3 persists embeddings
+	// across runs. Empty uses defaultEmbeddingCachePath.
+	EmbeddingCachePath string
+}
+
+// ProviderFactory constructs an AIProvider from cfg, reading whatever
+// credentials or endpoint configuration it still needs from the
+// environment for fields cfg leaves blank.
+type ProviderFactory func(cfg ProviderConfig) (AIProvider, error)
+
+var providerRegistry = map[string]ProviderFactory{}
+
+// RegisterProvider adds a named factory to the registry. Providers call
+// this from an init() in their own file, the same way database/sql
+// drivers register themselves.
+func RegisterProvider(name string, factory ProviderFactory) {
+	providerRegistry[name] = factory
+}
+
+// ProviderNames returns every registered provider name, sorted, for the
+// TUI's provider-picker overlay.
+func ProviderNames() []string {
+	names := make([]string, 0, len(providerRegistry))
+	for name := range providerRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewProvider constructs the named provider via its registered factory,
+// passing cfg through so it can honor a configured model/endpoint/timeout
+// instead of only its own hardcoded defaults. Unless cfg.DisableHybridMapping
+// is set, the result is wrapped in a hybridProvider so most columns resolve
+// against cfg's deterministic and (if cfg.EmbeddingProvider names one)
+// embedding passes before ever reaching name's API.
+func NewProvider(name string, cfg ProviderConfig) (AIProvider, error) {
+	factory, ok := providerRegistry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown AI provider: %s (available: %s)", name, strings.Join(ProviderNames(), ", "))
+	}
+	base, err := factory(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.DisableHybridMapping {
+		return base, nil
+	}
+
+	opts := HybridOptions{
+		SynonymsPath:       cfg.SynonymsPath,
+		MatchThreshold:     cfg.MatchThreshold,
+		EmbeddingThreshold: cfg.EmbeddingThreshold,
+	}
+	if cfg.EmbeddingProvider != "" {
+		embedder, err := NewEmbeddingProvider(cfg.EmbeddingProvider, cfg)
+		if err != nil {
+			logger.Warn("Failed to initialize embedding provider for hybrid mapping, skipping stage 3", "provider", cfg.EmbeddingProvider, "error", err)
+		} else {
+			opts.Embedder = embedder
+			cache, err := openEmbeddingCache(cfg.EmbeddingCachePath)
+			if err != nil {
+				logger.Warn("Failed to open embedding cache, continuing without one", "error", err)
+			} else {
+				opts.Cache = cache
+			}
+		}
+	}
+
+	return NewHybridProvider(base, opts)
+}
+
+// retryBaseDelay is the wait before the first retry; retryWithBackoff
+// doubles it after each subsequent failed attempt.
+const retryBaseDelay = 500 * time.Millisecond
+
+// retryWithBackoff calls fn until it succeeds or maxRetries additional
+// attempts have failed, doubling the wait between attempts starting at
+// retryBaseDelay. maxRetries <= 0 means fn runs exactly once. It gives up
+// early if ctx is cancelled while waiting.
+func retryWithBackoff(ctx context.Context, maxRetries int, fn func() error) error {
+	delay := retryBaseDelay
+	var lastErr error
+
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			logger.Warn("Retrying AI request after error", "attempt", attempt, "delay", delay, "previous_error", lastErr)
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			delay *= 2
+		}
+
+		if lastErr = fn(); lastErr == nil {
+			return nil
+		}
+	}
+
+	return lastErr
+}
+
+// buildMappingPrompt creates the shared prompt every provider sends,
+// asking the model to map each scanned column to a target column or
+// NO_MATCH. The response itself is constrained to mappingResponseSchema
+// by whatever structured-output mechanism the calling provider supports,
+// so the prompt only needs to describe the task, not the wire format.
+func buildMappingPrompt(scannedColumns, targetColumns []string) string {
+	logger.Debug("Building AI prompt", "scanned_count", len(scannedColumns), "target_count", len(targetColumns))
+
+	prompt := `You are an expert data analyst helping to map column names from various Excel files to a standardized target format.
+
+TASK: Map each scanned column to the most appropriate target column, or mark as "NO_MATCH" if uncertain.
+
+SCANNED COLUMNS (from various Excel files):
+`
+	for _, col := range scannedColumns {
+		prompt += fmt.Sprintf("- %s\n", col)
+	}
+
+	prompt += `
+TARGET COLUMNS (standardized format):
+`
+	for _, col := range targetColumns {
+		prompt += fmt.Sprintf("- %s\n", col)
+	}
+
+	prompt += `
+INSTRUCTIONS:
+1. Only suggest mappings you are confident about (>80% certainty)
+2. Consider semantic meaning, not just text similarity
+3. Map each scanned column to AT MOST ONE target column
+4. If uncertain or no clear match exists, use "NO_MATCH" as the target
+5. Return one entry per scanned column, including NO_MATCH ones
+6. For each entry, give a one-sentence reasoning for why it does (or does not) match
+
+Respond with a JSON object matching the required schema. Now provide mappings for the scanned columns:`
+
+	logger.Debug("Prompt built successfully", "final_length", len(prompt))
+	logger.Debug("Full prompt sent to AI", "prompt", prompt)
+	return prompt
+}
+
+// mappingResponseSchema is the structured-output contract every provider
+// asks the model to conform to: a single "mappings" array, one entry per
+// scanned column. It's expressed here as a generic JSON Schema document so
+// providers that accept JSON Schema directly (OpenAI, Ollama) can use it
+// as-is. Gemini uses geminiMappingSchema instead, the same schema written
+// out by hand as a *genai.Schema since its SDK wants a typed struct rather
+// than a raw document.
+var mappingResponseSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"mappings": map[string]any{
+			"type": "array",
+			"items": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"scanned":    map[string]any{"type": "string"},
+					"target":     map[string]any{"type": "string"},
+					"confidence": map[string]any{"type": "number"},
+					"reasoning":  map[string]any{"type": "string"},
+				},
+				"required": []string{"scanned", "target", "confidence", "reasoning"},
+			},
+		},
+	},
+	"required": []string{"mappings"},
+}
+
+// mappingResponse is the typed form of mappingResponseSchema, decoded with
+// encoding/json from whichever provider returned it.
+type mappingResponse struct {
+	Mappings []mappingResponseEntry `json:"mappings"`
+}
+
+type mappingResponseEntry struct {
+	Scanned    string  `json:"scanned"`
+	Target     string  `json:"target"`
+	Confidence float64 `json:"confidence"`
+	Reasoning  string  `json:"reasoning"`
+}
+
+// parseMappingResponse decodes a model's JSON response against
+// mappingResponse, drops NO_MATCH entries and anything below the
+// confidence floor, and rejects any target column the model hallucinated
+// by checking it against targetColumns. Shared by every provider.
+func parseMappingResponse(response string, targetColumns []string) ([]AIMapping, error) {
+	logger.Info("Parsing AI response", "response_length", len(response))
+	logger.Debug("Full raw API response", "response", response)
+
+	knownTargets := make(map[string]bool, len(targetColumns))
+	for _, target := range targetColumns {
+		knownTargets[target] = true
+	}
+
+	var parsed mappingResponse
+	if err := json.Unmarshal([]byte(extractJSONObject(response)), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode mapping response: %v", err)
+	}
+
+	var mappings []AIMapping
+	for _, entry := range parsed.Mappings {
+		scannedCol := strings.TrimSpace(entry.Scanned)
+		targetCol := strings.TrimSpace(entry.Target)
+
+		if targetCol == "" || targetCol == "NO_MATCH" || entry.Confidence < 0.6 {
+			continue
+		}
+		if !knownTargets[targetCol] {
+			logger.Warn("Dropping mapping to unknown target column", "scanned", scannedCol, "target", targetCol)
+			continue
+		}
+
+		logger.Debug("AI mapping reasoning", "scanned", scannedCol, "target", targetCol, "reasoning", entry.Reasoning)
+		mappings = append(mappings, AIMapping{
+			ScannedColumn: scannedCol,
+			TargetColumn:  targetCol,
+			Confidence:    entry.Confidence,
+			Reasoning:     entry.Reasoning,
+		})
+	}
+
+	logger.Info("Parsing completed", "final_mappings", len(mappings))
+	return mappings, nil
+}
+
+// extractJSONObject trims any prose a provider prepended or appended
+// around the JSON object despite being asked for structured output,
+// keeping only the outermost {...}. Providers that honor their native
+// constrained-decoding feature return a bare object and this is a no-op.
+func extractJSONObject(response string) string {
+	response = strings.TrimSpace(response)
+	start := strings.IndexByte(response, '{')
+	end := strings.LastIndexByte(response, '}')
+	if start == -1 || end == -1 || end < start {
+		return response
+	}
+	return response[start : end+1]
+}
+
+// chunkAndGenerate splits scannedColumns into chunks of at most chunkSize
+// and runs generate (a provider's single-batch call) over each, so very
+// large column sets don't blow a single request's token or rate limits.
+// Shared by providers that don't have their own batching strategy.
+func chunkAndGenerate(scannedColumns, targetColumns []string, chunkSize int, generate func(chunk []string) ([]AIMapping, error)) ([]AIMapping, error) {
+	var all []AIMapping
+	for i := 0; i < len(scannedColumns); i += chunkSize {
+		end := i + chunkSize
+		if end > len(scannedColumns) {
+			end = len(scannedColumns)
+		}
+		mappings, err := generate(scannedColumns[i:end])
+		if err != nil {
+			logger.Error("Failed to process chunk", "range", fmt.Sprintf("%d-%d", i+1, end), "error", err)
+			continue
+		}
+		all = append(all, mappings...)
+	}
+	return all, nil
+}
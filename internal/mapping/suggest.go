@@ -0,0 +1,202 @@
+package mapping
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"sheetFmt/internal/excel"
+)
+
+// SuggestOptions configures SuggestMappings.
+type SuggestOptions struct {
+	// Threshold is the minimum score (0-1) a scanned/target pair needs to
+	// be proposed as a mapping. Pairs at or below it are still returned,
+	// marked IsIgnored, so a reviewer can see what was considered and
+	// rejected. Zero defaults to 0.75.
+	Threshold float64
+}
+
+const defaultSuggestThreshold = 0.75
+
+// SuggestMappings proposes a best-guess ColumnMapping for each of scanned
+// against targets, using a blend of token-set Jaccard similarity and
+// normalized Damerau-Levenshtein distance. Assignment is one-to-one: once a
+// target is claimed by a higher-scoring scanned column it's no longer
+// offered to a lower-scoring one. Scanned columns whose best remaining
+// score doesn't clear opts.Threshold are still returned, with IsIgnored set
+// and TargetColumn left empty, so a reviewer can see what was skipped
+// rather than silently losing the row.
+func SuggestMappings(scanned []string, targets []string, opts SuggestOptions) []ColumnMapping {
+	threshold := opts.Threshold
+	if threshold <= 0 {
+		threshold = defaultSuggestThreshold
+	}
+
+	type candidate struct {
+		scannedIdx int
+		targetIdx  int
+		score      float64
+	}
+
+	mappings := make([]ColumnMapping, len(scanned))
+	var candidates []candidate
+
+	// Per scanned column, find its best-scoring target. A tie for the top
+	// score is treated the same as no match: both are ambiguous guesses,
+	// so the row is left for a human to fill in rather than guessed.
+	for si, s := range scanned {
+		sNorm := normalizeForSuggest(s)
+		bestTarget, bestScore, tie := -1, 0.0, false
+
+		for ti, t := range targets {
+			score := suggestScore(sNorm, normalizeForSuggest(t))
+			switch {
+			case score > bestScore:
+				bestTarget, bestScore, tie = ti, score, false
+			case score == bestScore && bestTarget >= 0:
+				tie = true
+			}
+		}
+
+		mappings[si] = ColumnMapping{ScannedColumn: s, Confidence: bestScore}
+		if bestTarget < 0 || tie || bestScore <= threshold {
+			mappings[si].IsIgnored = true
+			continue
+		}
+		candidates = append(candidates, candidate{scannedIdx: si, targetIdx: bestTarget, score: bestScore})
+	}
+
+	// Enforce one-to-one assignment: claim the highest-scoring candidate
+	// first, then drop any later candidate that reuses its target.
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+
+	assignedTarget := make(map[int]bool, len(targets))
+	for _, c := range candidates {
+		if assignedTarget[c.targetIdx] {
+			mappings[c.scannedIdx].IsIgnored = true
+			continue
+		}
+		assignedTarget[c.targetIdx] = true
+		mappings[c.scannedIdx].TargetColumn = targets[c.targetIdx]
+	}
+
+	return mappings
+}
+
+// normalizeForSuggest lowercases a column name on top of
+// excel.CleanColumnName's HTML/whitespace cleanup and turns every run of
+// characters other than letters and digits into a single space, so
+// "Customer-ID" and "customer id" score as identical tokens.
+func normalizeForSuggest(name string) string {
+	lower := strings.ToLower(excel.CleanColumnName(name))
+	return strings.Join(strings.FieldsFunc(lower, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// suggestScore blends token-set Jaccard similarity with normalized
+// Damerau-Levenshtein distance, per the 0.5/0.5 weighting the mapping
+// package's auto-suggest is specified to use.
+func suggestScore(a, b string) float64 {
+	if a == "" && b == "" {
+		return 1
+	}
+	if a == "" || b == "" {
+		return 0
+	}
+
+	jaccard := tokenSetJaccard(a, b)
+
+	maxLen := len(a)
+	if len(b) > maxLen {
+		maxLen = len(b)
+	}
+	levSim := 1 - float64(damerauLevenshtein(a, b))/float64(maxLen)
+
+	return 0.5*jaccard + 0.5*levSim
+}
+
+// tokenSetJaccard is the Jaccard index (intersection over union) of a and
+// b's whitespace-split token sets.
+func tokenSetJaccard(a, b string) float64 {
+	setA := tokenSet(a)
+	setB := tokenSet(b)
+	if len(setA) == 0 && len(setB) == 0 {
+		return 1
+	}
+
+	intersection := 0
+	for tok := range setA {
+		if setB[tok] {
+			intersection++
+		}
+	}
+	union := len(setA) + len(setB) - intersection
+	if union == 0 {
+		return 0
+	}
+	return float64(intersection) / float64(union)
+}
+
+func tokenSet(s string) map[string]bool {
+	tokens := strings.Fields(s)
+	set := make(map[string]bool, len(tokens))
+	for _, tok := range tokens {
+		set[tok] = true
+	}
+	return set
+}
+
+// damerauLevenshtein returns the Damerau-Levenshtein edit distance between
+// a and b (insertions, deletions, substitutions, and adjacent transpositions
+// all cost 1), via the classic dynamic-programming table.
+func damerauLevenshtein(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	la, lb := len(ar), len(br)
+
+	d := make([][]int, la+1)
+	for i := range d {
+		d[i] = make([]int, lb+1)
+		d[i][0] = i
+	}
+	for j := 0; j <= lb; j++ {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= la; i++ {
+		for j := 1; j <= lb; j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+
+			d[i][j] = min3(
+				d[i-1][j]+1,      // deletion
+				d[i][j-1]+1,      // insertion
+				d[i-1][j-1]+cost, // substitution
+			)
+
+			if i > 1 && j > 1 && ar[i-1] == br[j-2] && ar[i-2] == br[j-1] {
+				if t := d[i-2][j-2] + cost; t < d[i][j] {
+					d[i][j] = t // transposition
+				}
+			}
+		}
+	}
+
+	return d[la][lb]
+}
+
+func min3(a, b, c int) int {
+	m := a
+	if b < m {
+		m = b
+	}
+	if c < m {
+		m = c
+	}
+	return m
+}
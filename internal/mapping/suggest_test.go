@@ -0,0 +1,118 @@
+package mapping
+
+import "testing"
+
+func TestSuggestMappingsExactMatch(t *testing.T) {
+	got := SuggestMappings([]string{"Customer ID"}, []string{"Customer ID"}, SuggestOptions{})
+	if len(got) != 1 {
+		t.Fatalf("len(got) = %d, want 1", len(got))
+	}
+	if got[0].IsIgnored {
+		t.Errorf("exact match marked IsIgnored, got %+v", got[0])
+	}
+	if got[0].TargetColumn != "Customer ID" {
+		t.Errorf("TargetColumn = %q, want %q", got[0].TargetColumn, "Customer ID")
+	}
+	if got[0].Confidence != 1 {
+		t.Errorf("Confidence = %v, want 1", got[0].Confidence)
+	}
+}
+
+func TestSuggestMappingsBelowThresholdIsIgnored(t *testing.T) {
+	got := SuggestMappings([]string{"zzz"}, []string{"Customer ID"}, SuggestOptions{})
+	if len(got) != 1 {
+		t.Fatalf("len(got) = %d, want 1", len(got))
+	}
+	if !got[0].IsIgnored {
+		t.Errorf("expected a dissimilar pair to be ignored, got %+v", got[0])
+	}
+	if got[0].TargetColumn != "" {
+		t.Errorf("TargetColumn = %q, want empty for an ignored mapping", got[0].TargetColumn)
+	}
+}
+
+func TestSuggestMappingsOneToOneAssignment(t *testing.T) {
+	// Both scanned columns are closest to "Customer ID"; only the
+	// higher-scoring one should claim it, the other is left unassigned
+	// rather than stealing a worse-but-still-claimed target.
+	scanned := []string{"Customer ID", "Customer Id Number"}
+	targets := []string{"Customer ID"}
+
+	got := SuggestMappings(scanned, targets, SuggestOptions{})
+	if len(got) != 2 {
+		t.Fatalf("len(got) = %d, want 2", len(got))
+	}
+
+	claimed := 0
+	for _, m := range got {
+		if m.TargetColumn == "Customer ID" {
+			claimed++
+		}
+	}
+	if claimed != 1 {
+		t.Errorf("expected exactly one scanned column to claim the target, got %d", claimed)
+	}
+	if got[0].TargetColumn != "Customer ID" || !got[1].IsIgnored {
+		t.Errorf("expected the exact match to win the target and the looser match to be ignored, got %+v", got)
+	}
+}
+
+func TestSuggestMappingsTieIsTreatedAsNoMatch(t *testing.T) {
+	// "ab" is equidistant from both targets, so SuggestMappings should
+	// leave it unassigned rather than guessing between them.
+	got := SuggestMappings([]string{"ab"}, []string{"ac", "ad"}, SuggestOptions{Threshold: 0.1})
+	if len(got) != 1 {
+		t.Fatalf("len(got) = %d, want 1", len(got))
+	}
+	if !got[0].IsIgnored || got[0].TargetColumn != "" {
+		t.Errorf("expected a tied best score to be treated as no match, got %+v", got[0])
+	}
+}
+
+func TestSuggestScore(t *testing.T) {
+	if s := suggestScore("", ""); s != 1 {
+		t.Errorf("suggestScore(\"\", \"\") = %v, want 1", s)
+	}
+	if s := suggestScore("a", ""); s != 0 {
+		t.Errorf("suggestScore(\"a\", \"\") = %v, want 0", s)
+	}
+	if s := suggestScore("customer id", "customer id"); s != 1 {
+		t.Errorf("suggestScore on identical strings = %v, want 1", s)
+	}
+}
+
+func TestTokenSetJaccard(t *testing.T) {
+	if j := tokenSetJaccard("customer id", "customer id"); j != 1 {
+		t.Errorf("tokenSetJaccard on identical token sets = %v, want 1", j)
+	}
+	if j := tokenSetJaccard("customer id", "order total"); j != 0 {
+		t.Errorf("tokenSetJaccard on disjoint token sets = %v, want 0", j)
+	}
+	if j := tokenSetJaccard("customer id", "customer name"); j != 1.0/3.0 {
+		t.Errorf("tokenSetJaccard(\"customer id\", \"customer name\") = %v, want %v", j, 1.0/3.0)
+	}
+}
+
+func TestDamerauLevenshtein(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "abc", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"ab", "ba", 1}, // adjacent transposition costs 1, not 2
+	}
+	for _, c := range cases {
+		if got := damerauLevenshtein(c.a, c.b); got != c.want {
+			t.Errorf("damerauLevenshtein(%q, %q) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestNormalizeForSuggest(t *testing.T) {
+	if got := normalizeForSuggest("Customer-ID"); got != "customer id" {
+		t.Errorf("normalizeForSuggest(%q) = %q, want %q", "Customer-ID", got, "customer id")
+	}
+}
@@ -0,0 +1,77 @@
+package mapping
+
+import (
+	"os"
+
+	"sheetFmt/internal/logger"
+
+	"github.com/BurntSushi/toml"
+)
+
+// SynonymDict maps a normalized alias to the normalized canonical form it
+// should be treated as equal to, e.g. "cust id" -> "customer id". Lookups
+// in hybridProvider's stage 1 go through this after normalizeForHybrid, so
+// both the alias and the canonical form must already be normalized.
+type SynonymDict map[string]string
+
+// defaultSynonymsPath is where loadSynonymDict looks when HybridOptions
+// leaves SynonymsPath blank, mirroring config.FormatConfig's
+// TargetFormatFile convention of defaulting under configs/.
+const defaultSynonymsPath = "configs/synonyms.toml"
+
+// synonymsFile is the on-disk shape of the synonyms TOML file: a flat
+// alias-to-canonical table under [synonyms], left to the user to edit.
+//
+//	[synonyms]
+//	"cust id" = "customer id"
+//	"phone" = "phone number"
+type synonymsFile struct {
+	Synonyms map[string]string `toml:"synonyms"`
+}
+
+// builtinSynonyms covers the handful of abbreviations common enough across
+// spreadsheet exports to ship as a default, used whenever no synonyms file
+// is found on disk. A user-supplied file overlays these rather than
+// replacing them outright.
+var builtinSynonyms = SynonymDict{
+	"cust id": "customer id",
+	"custid":  "customer id",
+	"ph":      "phone",
+	"tel":     "phone",
+	"qty":     "quantity",
+	"amt":     "amount",
+	"desc":    "description",
+	"addr":    "address",
+	"dob":     "date of birth",
+	"ssn":     "social security number",
+}
+
+// loadSynonymDict reads the user-editable synonym dictionary from path,
+// overlaying it onto builtinSynonyms. A missing file is not an error: it
+// just means the built-ins are all hybridProvider has to work with.
+func loadSynonymDict(path string) (SynonymDict, error) {
+	if path == "" {
+		path = defaultSynonymsPath
+	}
+
+	dict := make(SynonymDict, len(builtinSynonyms))
+	for alias, canonical := range builtinSynonyms {
+		dict[alias] = canonical
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		logger.Debug("No synonyms file found, using built-ins only", "path", path)
+		return dict, nil
+	}
+
+	var file synonymsFile
+	if _, err := toml.DecodeFile(path, &file); err != nil {
+		return nil, err
+	}
+	for alias, canonical := range file.Synonyms {
+		dict[normalizeForHybrid(alias)] = normalizeForHybrid(canonical)
+	}
+
+	logger.Debug("Loaded synonym dictionary", "path", path, "entries", len(dict))
+	return dict, nil
+}
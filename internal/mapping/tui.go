@@ -1,10 +1,15 @@
 package mapping
 
 import (
+	"context"
 	"fmt"
 	"math"
+	"sheetFmt/internal/mapping/ui"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -17,11 +22,60 @@ const (
 	stateSelectTarget
 	stateConfirm
 	stateAILoading
+	stateProviderPicker
+	stateCommand
+	stateHistory
+	stateAIReview
 )
 
-// Messages for async operations
-type aiMappingsMsg map[string]string
-type aiErrorMsg error
+// historyCapacity bounds the undo/redo ring so a long mapping session
+// doesn't grow it unboundedly; far more than anyone undoes in practice.
+const historyCapacity = 200
+
+// defaultReviewThreshold seeds the :review overlay's confidence slider,
+// matching the ">80% certainty" instruction AIProvider prompts already ask for.
+const defaultReviewThreshold = 0.8
+
+// reviewThresholdStep is how far "["/"]" move the slider per press.
+const reviewThresholdStep = 0.05
+
+// Messages for async operations. Both carry the generation the
+// producing generateAIMappingsCmd was started with (the same field
+// aiProgressMsg stamps for the same reason, see below): Update compares
+// it against m.aiGeneration so a terminal message from a cancelled and
+// superseded session is dropped instead of clobbering the new session's
+// aiLoading/aiCancel/state/aiSuggestions bookkeeping.
+type aiMappingsMsg struct {
+	suggestions map[string]string
+	confidence  map[string]float64
+	generation  int
+}
+type aiErrorMsg struct {
+	err        error
+	generation int
+}
+
+// aiProgressMsg carries one incremental mapping parsed by a
+// StreamingAIProvider, together with a running done/total count, up to
+// the TUI while stateAILoading is showing. latest is applied to
+// m.aiSuggestions as soon as it's received, so results aren't lost if
+// the user cancels before the batch finishes.
+//
+// generation and channel identify which AI session this update came
+// from: generation is the model.aiGeneration value current when the
+// producing generateAIMappingsCmd was started, and channel is the
+// progressChan that same session sends on. Update compares generation
+// against m.aiGeneration to tell a stale session's update apart from
+// the current one, and always re-arms listenForAIProgress on channel
+// (not m.aiProgressChan) so a superseded session's channel keeps being
+// drained to its own close() instead of being abandoned.
+type aiProgressMsg struct {
+	done       int
+	total      int
+	latest     AIMapping
+	generation int
+	channel    chan aiProgressMsg
+}
 
 // UIConfig represents UI configuration settings
 type UIConfig struct {
@@ -33,15 +87,28 @@ type UIConfig struct {
 type model struct {
 	scannedColumns []string
 	targetColumns  []string
-	mappings       map[string]string // scanned -> target
-	ignored        map[string]bool   // scanned -> ignored
-	aiSuggestions  map[string]string // scanned -> AI suggested target
+	mappings       map[string]string  // scanned -> target
+	ignored        map[string]bool    // scanned -> ignored
+	aiSuggestions  map[string]string  // scanned -> AI suggested target
+	aiConfidence   map[string]float64 // scanned -> AI suggestion confidence, parallel to aiSuggestions
 
 	// UI state
 	state          state
 	currentScanned string
 	aiLoading      bool
 
+	// AI provider selection
+	aiProviderName    string
+	providerConfig    ProviderConfig
+	providerCursor    int
+	aiSuggestionCount int
+	aiTotal           int
+	aiLatestColumn    string
+	aiStartTime       time.Time
+	aiProgressChan    chan aiProgressMsg
+	aiGeneration      int
+	aiCancel          context.CancelFunc
+
 	// Grid navigation for scanned columns
 	page         int
 	row          int
@@ -55,13 +122,39 @@ type model struct {
 	targetPage    int
 	targetPerPage int
 
+	// Fuzzy filter over the target list, opened with "/". allTargetColumns
+	// holds the unfiltered order; targetColumns is re-ranked in place
+	// while a filter is active and restored when it's cleared.
+	allTargetColumns   []string
+	targetFilterActive bool
+	targetFilterInput  textinput.Model
+	targetFilterScores map[string]float64
+
 	// Screen dimensions
 	width  int
 	height int
 
-	// Progress tracking
-	mapped int
-	total  int
+	// Progress tracking. There is deliberately no tracked "mapped" counter:
+	// it used to be incremented/decremented ad hoc alongside mappings and
+	// ignored, which undo could desync. Counts are always derived from
+	// len(m.mappings)/len(m.ignored) instead.
+	total int
+
+	// Undo/redo history over mappings/ignored/aiSuggestions mutations, and
+	// the ":"-prefixed command line used to open the :history and :review
+	// overlays.
+	history      opHistory
+	commandInput textinput.Model
+	commandErr   string
+
+	// stateAIReview: pending AI suggestions sorted by confidence
+	// descending (reviewOrder), per-column checkbox state
+	// (reviewSelected), the cursor into reviewOrder, and the "["/"]"
+	// confidence slider used by "A" to bulk-check everything above it.
+	reviewOrder     []string
+	reviewSelected  map[string]bool
+	reviewCursor    int
+	reviewThreshold float64
 
 	// Styling
 	titleStyle     lipgloss.Style
@@ -76,25 +169,34 @@ type model struct {
 }
 
 // Initialize the model with config
-func initialModel(scannedColumns, targetColumns []string, uiConfig UIConfig) model {
+func initialModel(scannedColumns, targetColumns []string, uiConfig UIConfig, providerConfig ProviderConfig, providerName string) model {
+	if providerName == "" {
+		providerName = DefaultProviderName
+	}
 	return model{
-		scannedColumns: scannedColumns,
-		targetColumns:  targetColumns,
-		mappings:       make(map[string]string),
-		ignored:        make(map[string]bool),
-		aiSuggestions:  make(map[string]string),
-		state:          stateSelectScanned,
-		page:           0,
-		row:            0,
-		col:            0,
-		colsPerRow:     uiConfig.ColumnsPerRow,
-		rowsPerPage:    uiConfig.RowsPerPage,
-		itemsPerPage:   uiConfig.ColumnsPerRow * uiConfig.RowsPerPage,
-		targetCursor:   0,
-		targetPage:     0,
-		targetPerPage:  15,
-		total:          len(scannedColumns),
-		aiLoading:      false,
+		scannedColumns:   scannedColumns,
+		targetColumns:    targetColumns,
+		allTargetColumns: targetColumns,
+		mappings:         make(map[string]string),
+		ignored:          make(map[string]bool),
+		aiSuggestions:    make(map[string]string),
+		aiConfidence:     make(map[string]float64),
+		state:            stateSelectScanned,
+		page:             0,
+		row:              0,
+		col:              0,
+		colsPerRow:       uiConfig.ColumnsPerRow,
+		rowsPerPage:      uiConfig.RowsPerPage,
+		itemsPerPage:     uiConfig.ColumnsPerRow * uiConfig.RowsPerPage,
+		targetCursor:     0,
+		targetPage:       0,
+		targetPerPage:    15,
+		total:            len(scannedColumns),
+		aiLoading:        false,
+		aiProviderName:   providerName,
+		providerConfig:   providerConfig,
+		history:          newOpHistory(historyCapacity),
+		reviewThreshold:  defaultReviewThreshold,
 
 		titleStyle: lipgloss.NewStyle().
 			Bold(true).
@@ -134,57 +236,77 @@ func (m model) Init() tea.Cmd {
 	return nil
 }
 
-// Command to generate AI mappings asynchronously
-func generateAIMappingsCmd(unmappedColumns, targetColumns []string) tea.Cmd {
+// Command to generate AI mappings asynchronously against the given
+// provider, bound to ctx so Esc (updateAILoading) can cancel the
+// in-flight request by cancelling ctx instead of merely hiding the
+// loading screen. If the provider supports streaming, each mapping is
+// reported on progressChan as it's parsed via listenForAIProgress, so
+// partial results can land in m.aiSuggestions even if the user cancels
+// before the batch finishes. generation is stamped onto every
+// aiProgressMsg sent so Update can recognize updates from a session
+// that's since been cancelled and replaced.
+func generateAIMappingsCmd(ctx context.Context, provider AIProvider, unmappedColumns, targetColumns []string, progressChan chan aiProgressMsg, generation int) tea.Cmd {
 	return func() tea.Msg {
 		// Initialize debug logging
 		if debugLogger == nil {
 			initDebugLogger()
 		}
 
-		debugLog("Starting AI mapping generation for %d unmapped columns", len(unmappedColumns))
-
-		apiKey := GetGeminiAPIKey()
-		if apiKey == "" {
-			err := fmt.Errorf("GEMINI_API_KEY not found in environment variables")
-			debugLog("ERROR: %v", err)
-			saveAIMappingsToFile(unmappedColumns, targetColumns, nil, err)
-			return aiErrorMsg(err)
-		}
+		defer close(progressChan)
+		defer provider.Close()
 
-		debugLog("API key found, initializing AI mapper")
+		debugLog("Starting AI mapping generation via %s for %d unmapped columns", provider.Name(), len(unmappedColumns))
 
-		aiMapper, err := NewAIMapper(apiKey)
-		if err != nil {
-			debugLog("ERROR: Failed to initialize AI mapper: %v", err)
-			saveAIMappingsToFile(unmappedColumns, targetColumns, nil, err)
-			return aiErrorMsg(fmt.Errorf("failed to initialize AI mapper: %v", err))
+		var aiMappings []AIMapping
+		var err error
+		if streaming, ok := provider.(StreamingAIProvider); ok {
+			done := 0
+			aiMappings, err = streaming.GenerateColumnMappingsStream(ctx, unmappedColumns, targetColumns, func(mapping AIMapping) {
+				done++
+				progressChan <- aiProgressMsg{done: done, total: len(unmappedColumns), latest: mapping, generation: generation, channel: progressChan}
+			})
+		} else {
+			aiMappings, err = provider.GenerateColumnMappings(ctx, unmappedColumns, targetColumns)
 		}
-		defer aiMapper.Close()
-
-		debugLog("Sending request to AI with %d unmapped columns and %d target columns", len(unmappedColumns), len(targetColumns))
-
-		aiMappings, err := aiMapper.GenerateColumnMappings(unmappedColumns, targetColumns)
 		if err != nil {
-			debugLog("ERROR: AI generation failed: %v", err)
-			saveAIMappingsToFile(unmappedColumns, targetColumns, nil, err)
-			return aiErrorMsg(fmt.Errorf("failed to generate AI mappings: %v", err))
+			if ctx.Err() != nil {
+				debugLog("AI generation cancelled by user")
+			} else {
+				debugLog("ERROR: AI generation failed: %v", err)
+				saveAIMappingsToFile(unmappedColumns, targetColumns, nil, err)
+			}
+			return aiErrorMsg{err: fmt.Errorf("failed to generate AI mappings via %s: %v", provider.Name(), err), generation: generation}
 		}
 
 		debugLog("AI generation completed successfully, received %d mappings", len(aiMappings))
-
-		// Save debug info to file
 		saveAIMappingsToFile(unmappedColumns, targetColumns, aiMappings, nil)
 
-		// Convert to map
+		// Convert to maps, keeping the confidence score alongside the
+		// suggestion instead of discarding it.
 		suggestions := make(map[string]string)
+		confidence := make(map[string]float64)
 		for _, mapping := range aiMappings {
 			suggestions[mapping.ScannedColumn] = mapping.TargetColumn
+			confidence[mapping.ScannedColumn] = mapping.Confidence
 			debugLog("AI suggested: '%s' → '%s' (%.2f confidence)", mapping.ScannedColumn, mapping.TargetColumn, mapping.Confidence)
 		}
 
 		debugLog("Returning %d suggestions to TUI", len(suggestions))
-		return aiMappingsMsg(suggestions)
+		return aiMappingsMsg{suggestions: suggestions, confidence: confidence, generation: generation}
+	}
+}
+
+// listenForAIProgress reads one value off progressChan and reports it as
+// an aiProgressMsg, or returns nil once the channel is closed. Update
+// re-issues this after every aiProgressMsg to keep listening for the next
+// one, the standard Bubble Tea pattern for draining a background channel.
+func listenForAIProgress(progressChan chan aiProgressMsg) tea.Cmd {
+	return func() tea.Msg {
+		update, ok := <-progressChan
+		if !ok {
+			return nil
+		}
+		return update
 	}
 }
 
@@ -201,27 +323,91 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case aiMappingsMsg:
+		// Drop a result from a session that's already been cancelled and
+		// superseded (Esc then "a" before the old goroutine's aiMappingsMsg
+		// arrived): applying it here would wipe the new session's
+		// in-progress aiLoading/state instead of the old one's.
+		if msg.generation != m.aiGeneration {
+			return m, nil
+		}
+
 		// AI mappings received
 		m.aiLoading = false
+		m.aiCancel = nil
 		m.state = stateSelectScanned
 
-		// Only add AI suggestions for unmapped columns
-		for scanned, target := range msg {
-			// Skip if already mapped or ignored
-			if _, mapped := m.mappings[scanned]; !mapped && !m.ignored[scanned] {
-				m.aiSuggestions[scanned] = target
+		// Only add AI suggestions not already landed via a streamed
+		// aiProgressMsg, for columns that are still unmapped.
+		added := make(map[string]string)
+		addedConfidence := make(map[string]float64)
+		for scanned, target := range msg.suggestions {
+			if _, mapped := m.mappings[scanned]; mapped || m.ignored[scanned] {
+				continue
+			}
+			if _, landed := m.aiSuggestions[scanned]; landed {
+				continue
 			}
+			added[scanned] = target
+			addedConfidence[scanned] = msg.confidence[scanned]
+		}
+		if len(added) > 0 {
+			m.history.record(&m, newBulkAIOp(&m, added, addedConfidence))
+		}
+		if len(m.aiSuggestions) > 0 {
+			m.enterAIReview()
 		}
 
 		return m, nil
 
 	case aiErrorMsg:
-		// AI error occurred
+		// Same staleness check as aiMappingsMsg: a superseded session's
+		// error must not clobber the new session's bookkeeping.
+		if msg.generation != m.aiGeneration {
+			return m, nil
+		}
+
+		// AI error occurred, or the request was cancelled via Esc. Either
+		// way, whatever aiProgressMsg updates already landed in
+		// m.aiSuggestions before the error stay put.
 		m.aiLoading = false
+		m.aiCancel = nil
 		m.state = stateSelectScanned
 		// Could show error message in status area if needed
 		return m, nil
 
+	case aiProgressMsg:
+		// One incremental mapping from a StreamingAIProvider. generateAIMappingsCmd
+		// keeps sending on msg.channel until the batch finishes or ctx.Err()
+		// is observed, regardless of what the TUI does meanwhile, so we must
+		// keep re-arming listenForAIProgress on msg.channel specifically
+		// (not m.aiProgressChan, which may have since been replaced by a
+		// newer AI session) so that channel stays drained all the way to
+		// its close(); otherwise the producer blocks forever on an unread
+		// send, leaking the goroutine along with its deferred
+		// provider.Close().
+		//
+		// msg.generation may belong to a session that's already been
+		// cancelled and superseded by a new one (Esc followed by "a"
+		// before the old goroutine noticed ctx.Err()): m.aiLoading and
+		// m.aiProgressChan would then reflect the new session, not this
+		// message's. Comparing against m.aiGeneration catches that case
+		// even though m.aiLoading is true again, so a stale update never
+		// lands on the wrong session's state.
+		if msg.generation != m.aiGeneration || !m.aiLoading {
+			return m, listenForAIProgress(msg.channel)
+		}
+		// Apply it to m.aiSuggestions immediately so it survives a later
+		// cancel, and keep listening for the next one.
+		m.aiSuggestionCount = msg.done
+		m.aiTotal = msg.total
+		m.aiLatestColumn = msg.latest.ScannedColumn
+		if _, mapped := m.mappings[msg.latest.ScannedColumn]; !mapped && !m.ignored[msg.latest.ScannedColumn] {
+			added := map[string]string{msg.latest.ScannedColumn: msg.latest.TargetColumn}
+			confidence := map[string]float64{msg.latest.ScannedColumn: msg.latest.Confidence}
+			m.history.record(&m, newBulkAIOp(&m, added, confidence))
+		}
+		return m, listenForAIProgress(msg.channel)
+
 	case tea.KeyMsg:
 		switch m.state {
 		case stateSelectScanned:
@@ -232,6 +418,14 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateConfirm(msg)
 		case stateAILoading:
 			return m.updateAILoading(msg)
+		case stateProviderPicker:
+			return m.updateProviderPicker(msg)
+		case stateCommand:
+			return m.updateCommand(msg)
+		case stateHistory:
+			return m.updateHistory(msg)
+		case stateAIReview:
+			return m.updateAIReview(msg)
 		}
 	}
 	return m, nil
@@ -280,6 +474,8 @@ func (m model) updateSelectScanned(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if currentIdx < len(m.scannedColumns) {
 			m.currentScanned = m.scannedColumns[currentIdx]
 			m.state = stateSelectTarget
+			m.targetFilterActive = false
+			m.applyTargetFilter("")
 
 			// Check if there's an AI suggestion for this column
 			if aiTarget, hasAI := m.aiSuggestions[m.currentScanned]; hasAI {
@@ -303,18 +499,27 @@ func (m model) updateSelectScanned(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if currentIdx < len(m.scannedColumns) {
 			scanned := m.scannedColumns[currentIdx]
 			if m.ignored[scanned] {
-				delete(m.ignored, scanned)
-				delete(m.mappings, scanned)
-				m.mapped--
+				m.history.record(&m, newUnmapOp(&m, scanned))
 			} else {
-				m.ignored[scanned] = true
-				delete(m.mappings, scanned)
-				// Remove AI suggestion if ignoring
-				delete(m.aiSuggestions, scanned)
-				m.mapped++
+				m.history.record(&m, newIgnoreOp(&m, scanned))
 			}
 		}
 
+	case "u":
+		m.history.undo(&m)
+
+	case "ctrl+r":
+		m.history.redo(&m)
+
+	case ":":
+		m.state = stateCommand
+		m.commandErr = ""
+		m.commandInput = textinput.New()
+		m.commandInput.Placeholder = "history"
+		m.commandInput.Prompt = ": "
+		m.commandInput.Focus()
+		return m, textinput.Blink
+
 	case "a":
 		// Generate AI mappings for unmapped columns only
 		if !m.aiLoading {
@@ -331,11 +536,40 @@ func (m model) updateSelectScanned(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				break
 			}
 
+			provider, err := NewProvider(m.aiProviderName, m.providerConfig)
+			if err != nil {
+				// Treat a missing/misconfigured provider like any other
+				// AI error instead of crashing the TUI.
+				return m, func() tea.Msg { return aiErrorMsg{err: err, generation: m.aiGeneration} }
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			m.aiCancel = cancel
 			m.aiLoading = true
+			m.aiSuggestionCount = 0
+			m.aiTotal = len(unmappedColumns)
+			m.aiLatestColumn = ""
+			m.aiStartTime = time.Now()
 			m.state = stateAILoading
-			return m, generateAIMappingsCmd(unmappedColumns, m.targetColumns)
+			m.aiProgressChan = make(chan aiProgressMsg)
+			m.aiGeneration++
+			return m, tea.Batch(
+				generateAIMappingsCmd(ctx, provider, unmappedColumns, m.targetColumns, m.aiProgressChan, m.aiGeneration),
+				listenForAIProgress(m.aiProgressChan),
+			)
 		}
 
+	case "p":
+		// Open the provider picker
+		names := ProviderNames()
+		for i, name := range names {
+			if name == m.aiProviderName {
+				m.providerCursor = i
+				break
+			}
+		}
+		m.state = stateProviderPicker
+
 	case "n":
 		// Move to next unmapped column
 		m.moveToNextUnmapped()
@@ -347,12 +581,181 @@ func (m model) updateSelectScanned(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+func (m model) updateProviderPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	names := ProviderNames()
+
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc":
+		m.state = stateSelectScanned
+	case "up", "k":
+		if m.providerCursor > 0 {
+			m.providerCursor--
+		}
+	case "down", "j":
+		if m.providerCursor < len(names)-1 {
+			m.providerCursor++
+		}
+	case "enter":
+		if m.providerCursor < len(names) {
+			m.aiProviderName = names[m.providerCursor]
+		}
+		m.state = stateSelectScanned
+	}
+	return m, nil
+}
+
+// updateCommand handles the ":"-prefixed command line. Known commands are
+// "history", opening the :history overlay, and "review", (re)opening the
+// AI-suggestion review overlay; anything else is reported back on the
+// command line instead of silently discarded.
+func (m model) updateCommand(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.state = stateSelectScanned
+		m.commandInput.Blur()
+		return m, nil
+	case tea.KeyEnter:
+		switch strings.TrimSpace(m.commandInput.Value()) {
+		case "history":
+			m.commandInput.Blur()
+			m.state = stateHistory
+		case "review":
+			m.commandInput.Blur()
+			if len(m.aiSuggestions) == 0 {
+				m.commandErr = "no pending AI suggestions to review"
+				m.state = stateSelectScanned
+			} else {
+				m.enterAIReview()
+			}
+		case "":
+			m.state = stateSelectScanned
+		default:
+			m.commandErr = fmt.Sprintf("unknown command: %s", m.commandInput.Value())
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.commandInput, cmd = m.commandInput.Update(msg)
+	return m, cmd
+}
+
+// updateHistory handles the read-only :history overlay: "u"/"ctrl+r" undo
+// and redo in place so the effect is visible immediately, and anything
+// else closes it.
+func (m model) updateHistory(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "u":
+		m.history.undo(&m)
+	case "ctrl+r":
+		m.history.redo(&m)
+	default:
+		m.state = stateSelectScanned
+	}
+	return m, nil
+}
+
+// updateAIReview handles the :review overlay: checkbox navigation and
+// toggling, the "["/"]" confidence slider, "A" to bulk-check everything
+// at or above it, "R" to drop the checked columns from consideration
+// entirely, and Enter to commit the checked columns into m.mappings as a
+// single undoable BulkAcceptOp.
+func (m model) updateAIReview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc":
+		m.state = stateSelectScanned
+	case "up", "k":
+		if m.reviewCursor > 0 {
+			m.reviewCursor--
+		}
+	case "down", "j":
+		if m.reviewCursor < len(m.reviewOrder)-1 {
+			m.reviewCursor++
+		}
+	case " ":
+		if m.reviewCursor < len(m.reviewOrder) {
+			scanned := m.reviewOrder[m.reviewCursor]
+			m.reviewSelected[scanned] = !m.reviewSelected[scanned]
+		}
+	case "[":
+		m.reviewThreshold -= reviewThresholdStep
+		if m.reviewThreshold < 0 {
+			m.reviewThreshold = 0
+		}
+	case "]":
+		m.reviewThreshold += reviewThresholdStep
+		if m.reviewThreshold > 1 {
+			m.reviewThreshold = 1
+		}
+	case "A":
+		for _, scanned := range m.reviewOrder {
+			if m.aiConfidence[scanned] >= m.reviewThreshold {
+				m.reviewSelected[scanned] = true
+			}
+		}
+	case "R":
+		remaining := m.reviewOrder[:0]
+		for _, scanned := range m.reviewOrder {
+			if m.reviewSelected[scanned] {
+				delete(m.aiSuggestions, scanned)
+				delete(m.aiConfidence, scanned)
+				delete(m.reviewSelected, scanned)
+			} else {
+				remaining = append(remaining, scanned)
+			}
+		}
+		m.reviewOrder = remaining
+		if m.reviewCursor >= len(m.reviewOrder) {
+			m.reviewCursor = len(m.reviewOrder) - 1
+		}
+		if m.reviewCursor < 0 {
+			m.reviewCursor = 0
+		}
+	case "enter":
+		accepted := make(map[string]string)
+		for _, scanned := range m.reviewOrder {
+			if m.reviewSelected[scanned] {
+				accepted[scanned] = m.aiSuggestions[scanned]
+			}
+		}
+		if len(accepted) > 0 {
+			m.history.record(&m, newBulkAcceptOp(&m, accepted))
+		}
+		m.state = stateSelectScanned
+		m.moveToNextUnmapped()
+	}
+	return m, nil
+}
+
 func (m model) updateSelectTarget(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.targetFilterActive {
+		return m.updateTargetFilter(msg)
+	}
+
 	switch msg.String() {
 	case "ctrl+c", "q":
 		return m, tea.Quit
 	case "esc":
 		m.state = stateSelectScanned
+	case "u":
+		m.history.undo(&m)
+	case "ctrl+r":
+		m.history.redo(&m)
+	case "/":
+		m.targetFilterActive = true
+		m.targetFilterInput = textinput.New()
+		m.targetFilterInput.Placeholder = "filter target columns..."
+		m.targetFilterInput.Prompt = "/ "
+		m.targetFilterInput.Focus()
+		return m, textinput.Blink
 	case "up", "k":
 		if m.targetCursor > 0 {
 			m.targetCursor--
@@ -380,25 +783,105 @@ func (m model) updateSelectTarget(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Map the columns
 		targetIdx := m.targetPage*m.targetPerPage + m.targetCursor
 		if targetIdx < len(m.targetColumns) {
-			target := m.targetColumns[targetIdx]
-
-			// Remove any previous mapping for this scanned column
-			if _, exists := m.mappings[m.currentScanned]; !exists {
-				m.mapped++
-			}
+			m.confirmTargetMapping(m.targetColumns[targetIdx])
+		}
+	}
+	return m, nil
+}
 
-			m.mappings[m.currentScanned] = target
-			delete(m.ignored, m.currentScanned)
-			// Remove AI suggestion since it's now manually confirmed
-			delete(m.aiSuggestions, m.currentScanned)
+// updateTargetFilter handles key events while the "/" fuzzy filter is
+// open: navigation and Enter/Esc are intercepted before anything else is
+// forwarded to the textinput, the same split Helix's picker uses between
+// picker motion and query editing.
+func (m model) updateTargetFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.targetFilterActive = false
+		m.targetFilterInput.Blur()
+		m.applyTargetFilter("")
+		return m, nil
+	case tea.KeyEnter:
+		if len(m.targetColumns) > 0 {
+			m.confirmTargetMapping(m.targetColumns[0])
+			m.targetFilterActive = false
+			m.targetFilterInput.Blur()
+		}
+		return m, nil
+	case tea.KeyUp:
+		if m.targetCursor > 0 {
+			m.targetCursor--
+		} else if m.targetPage > 0 {
+			m.targetPage--
+			m.targetCursor = m.targetPerPage - 1
+		}
+		return m, nil
+	case tea.KeyDown:
+		maxCursor := m.getMaxTargetCursor()
+		if m.targetCursor < maxCursor {
+			m.targetCursor++
+		} else if m.hasNextTargetPage() {
+			m.targetPage++
+			m.targetCursor = 0
+		}
+		return m, nil
+	}
 
-			m.state = stateSelectScanned
+	var cmd tea.Cmd
+	m.targetFilterInput, cmd = m.targetFilterInput.Update(msg)
+	m.applyTargetFilter(m.targetFilterInput.Value())
+	return m, cmd
+}
 
-			// Move to next unmapped column
-			m.moveToNextUnmapped()
+// applyTargetFilter re-ranks m.targetColumns against query using
+// fuzzyScore, dropping non-matches, and resets paging to the top of the
+// re-ranked list. An empty query restores the original target order.
+func (m *model) applyTargetFilter(query string) {
+	if query == "" {
+		m.targetColumns = append([]string(nil), m.allTargetColumns...)
+		m.targetFilterScores = nil
+	} else {
+		type scored struct {
+			name  string
+			score float64
+		}
+		matches := make([]scored, 0, len(m.allTargetColumns))
+		for _, name := range m.allTargetColumns {
+			if score, ok := fuzzyScore(query, name); ok {
+				matches = append(matches, scored{name, score})
+			}
+		}
+		sort.SliceStable(matches, func(i, j int) bool {
+			return matches[i].score > matches[j].score
+		})
+
+		m.targetColumns = make([]string, len(matches))
+		m.targetFilterScores = make(map[string]float64, len(matches))
+		for i, match := range matches {
+			m.targetColumns[i] = match.name
+			m.targetFilterScores[match.name] = match.score
 		}
 	}
-	return m, nil
+
+	m.targetCursor = 0
+	m.targetPage = 0
+}
+
+// confirmTargetMapping maps the current scanned column to target and
+// advances to the next unmapped column, the shared tail of both the
+// plain and filtered Enter handlers. Picking the column's own pending AI
+// suggestion records an AcceptAIOp instead of a MapOp, so :history shows
+// it was accepted rather than manually chosen.
+func (m *model) confirmTargetMapping(target string) {
+	if aiTarget, hasAI := m.aiSuggestions[m.currentScanned]; hasAI && aiTarget == target {
+		m.history.record(m, newAcceptAIOp(m, m.currentScanned, target))
+	} else {
+		m.history.record(m, newMapOp(m, m.currentScanned, target))
+	}
+
+	m.state = stateSelectScanned
+	m.moveToNextUnmapped()
 }
 
 func (m model) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
@@ -418,7 +901,11 @@ func (m model) updateAILoading(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "ctrl+c", "q":
 		return m, tea.Quit
 	case "esc":
-		// Cancel AI loading and go back
+		// Cancel the in-flight request (any aiProgressMsg updates that
+		// already landed in m.aiSuggestions are kept) and go back.
+		if m.aiCancel != nil {
+			m.aiCancel()
+		}
 		m.aiLoading = false
 		m.state = stateSelectScanned
 	}
@@ -525,6 +1012,29 @@ func (m *model) moveToNextUnmapped() {
 	// (This means all columns are either mapped or ignored)
 }
 
+// enterAIReview (re)builds the :review overlay's pending-suggestion list,
+// sorted by confidence descending, and switches to stateAIReview. Every
+// column at or above reviewThreshold starts checked; this is re-derived
+// from scratch each time so a raised/lowered slider ("["/"]") is
+// reflected the next time the overlay is (re)opened.
+func (m *model) enterAIReview() {
+	m.reviewOrder = make([]string, 0, len(m.aiSuggestions))
+	for scanned := range m.aiSuggestions {
+		m.reviewOrder = append(m.reviewOrder, scanned)
+	}
+	sort.SliceStable(m.reviewOrder, func(i, j int) bool {
+		return m.aiConfidence[m.reviewOrder[i]] > m.aiConfidence[m.reviewOrder[j]]
+	})
+
+	m.reviewSelected = make(map[string]bool, len(m.reviewOrder))
+	for _, scanned := range m.reviewOrder {
+		m.reviewSelected[scanned] = m.aiConfidence[scanned] >= m.reviewThreshold
+	}
+
+	m.reviewCursor = 0
+	m.state = stateAIReview
+}
+
 func (m model) View() string {
 	switch m.state {
 	case stateSelectScanned:
@@ -535,47 +1045,76 @@ func (m model) View() string {
 		return m.viewConfirm()
 	case stateAILoading:
 		return m.viewAILoading()
+	case stateProviderPicker:
+		return m.viewProviderPicker()
+	case stateCommand:
+		return m.viewCommand()
+	case stateHistory:
+		return m.viewHistory()
+	case stateAIReview:
+		return m.viewAIReview()
 	}
 	return ""
 }
 
+// viewSelectScanned composes the whole screen as a ui.Grid: a header
+// block (title/progress/page info) spanning the full width, the
+// colsPerRow x rowsPerPage column grid itself, and a help footer, all
+// placed via Grid.Add(...).At(row, col) instead of hand-joining strings,
+// so the layout recomputes cleanly on every tea.WindowSizeMsg.
 func (m model) viewSelectScanned() string {
-	var b strings.Builder
+	totalPages := int(math.Ceil(float64(len(m.scannedColumns)) / float64(m.itemsPerPage)))
+	if totalPages == 0 {
+		totalPages = 1
+	}
 
-	// Title
-	title := m.titleStyle.Width(m.width).Render("Column Mapping Tool")
-	b.WriteString(title)
-	b.WriteString("\n\n")
+	colSpecs := make([]ui.DimSpec, m.colsPerRow)
+	for i := range colSpecs {
+		colSpecs[i] = ui.Weight(1)
+	}
+
+	const headerRows = 6 // title, blank, progress, blank, page info, blank
+	rowSpecs := make([]ui.DimSpec, 0, headerRows+m.rowsPerPage+2)
+	for i := 0; i < headerRows; i++ {
+		rowSpecs = append(rowSpecs, ui.Exact(1))
+	}
+	for i := 0; i < m.rowsPerPage; i++ {
+		rowSpecs = append(rowSpecs, ui.Exact(1))
+	}
+	rowSpecs = append(rowSpecs, ui.Exact(1) /* blank */, ui.Exact(1) /* help */)
+
+	grid := ui.NewGrid(rowSpecs, colSpecs)
+	grid.SetSize(m.width, len(rowSpecs))
+
+	grid.Add(ui.StyledText{Content: "Column Mapping Tool", Style: m.titleStyle}).
+		At(0, 0).Span(1, m.colsPerRow)
 
-	// Progress
 	aiCount := len(m.aiSuggestions)
 	progress := fmt.Sprintf("Progress: %d/%d mapped, %d AI suggestions, %d ignored",
 		len(m.mappings), m.total, aiCount, len(m.ignored))
-	b.WriteString(m.progressStyle.Render(progress))
-	b.WriteString("\n\n")
+	grid.Add(ui.StyledText{Content: progress, Style: m.progressStyle}).
+		At(2, 0).Span(1, m.colsPerRow)
 
-	// Page info
-	totalPages := int(math.Ceil(float64(len(m.scannedColumns)) / float64(m.itemsPerPage)))
-	if totalPages == 0 {
-		totalPages = 1
-	}
 	pageInfo := fmt.Sprintf("Page %d/%d", m.page+1, totalPages)
-	b.WriteString(m.helpStyle.Render(pageInfo))
-	b.WriteString("\n\n")
-
-	// Calculate column width dynamically
-	columnWidth := (m.width - 4) / m.colsPerRow // Account for padding and spacing
-	if columnWidth < 10 {
-		columnWidth = 10 // Minimum width
-	}
+	grid.Add(ui.StyledText{Content: pageInfo, Style: m.helpStyle}).
+		At(4, 0).Span(1, m.colsPerRow)
 
-	// Column grid - use configurable rows
+	dataRow := headerRows
+	colWidths := grid.ColWidths()
 	for row := 0; row < m.rowsPerPage; row++ {
-		var rowItems []string
 		for col := 0; col < m.colsPerRow; col++ {
 			idx := m.page*m.itemsPerPage + row*m.colsPerRow + col
 			if idx >= len(m.scannedColumns) {
-				break
+				continue
+			}
+
+			cellWidth := colWidths[col]
+			if cellWidth < 10 {
+				cellWidth = 10 // Minimum width
+			}
+			textWidth := cellWidth - 2
+			if textWidth < 1 {
+				textWidth = 1
 			}
 
 			column := m.scannedColumns[idx]
@@ -602,28 +1141,141 @@ func (m model) viewSelectScanned() string {
 				style = m.selectedStyle
 			}
 
-			// Truncate based on calculated column width
-			if len(displayText) > columnWidth-2 {
-				displayText = displayText[:columnWidth-5] + "..."
+			// Truncate based on negotiated column width; StyledText pads
+			// the rest of the cell for us.
+			if len(displayText) > textWidth {
+				if textWidth > 3 {
+					displayText = displayText[:textWidth-3] + "..."
+				} else {
+					displayText = displayText[:textWidth]
+				}
 			}
 
-			// Use calculated width for consistent spacing
-			displayText = fmt.Sprintf("%-*s", columnWidth-2, displayText)
+			grid.Add(ui.StyledText{Content: displayText, Style: style}).At(dataRow+row, col)
+		}
+	}
+
+	help := fmt.Sprintf("↑↓←→: navigate | Enter: select/confirm AI | i: ignore | u: undo | ctrl+r: redo | a: AI mapping (%s) | p: pick provider | n: next unmapped | s: save | :history/:review | q: quit", m.aiProviderName)
+	grid.Add(ui.StyledText{Content: help, Style: m.helpStyle}).
+		At(dataRow+m.rowsPerPage+1, 0).Span(1, m.colsPerRow)
 
-			rowItems = append(rowItems, style.Render(displayText))
+	return grid.Render()
+}
+
+func (m model) viewProviderPicker() string {
+	var b strings.Builder
+
+	b.WriteString(m.titleStyle.Render("Select AI Provider"))
+	b.WriteString("\n\n")
+
+	names := ProviderNames()
+	for i, name := range names {
+		var style lipgloss.Style
+		prefix := "  "
+
+		if i == m.providerCursor {
+			style = m.selectedStyle
+			prefix = "> "
+		} else {
+			style = m.normalStyle
 		}
 
-		if len(rowItems) > 0 {
-			b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, rowItems...))
-			b.WriteString("\n")
+		label := name
+		if name == m.aiProviderName {
+			label += " (current)"
 		}
+		b.WriteString(style.Render(prefix + label))
+		b.WriteString("\n")
 	}
 
 	b.WriteString("\n")
+	b.WriteString(m.helpStyle.Render("↑↓: navigate | Enter: select | Esc: cancel | q: quit"))
 
-	// Help
-	help := "↑↓←→: navigate | Enter: select/confirm AI | i: ignore | a: AI mapping | n: next unmapped | s: save | q: quit"
-	b.WriteString(m.helpStyle.Render(help))
+	return b.String()
+}
+
+// viewCommand renders the ":"-prefixed command line under the tool's
+// title, with the last command's error (if any) below it.
+func (m model) viewCommand() string {
+	var b strings.Builder
+
+	title := m.titleStyle.Width(m.width).Render("Column Mapping Tool")
+	b.WriteString(title)
+	b.WriteString("\n\n")
+	b.WriteString(m.commandInput.View())
+	b.WriteString("\n")
+	if m.commandErr != "" {
+		b.WriteString(m.helpStyle.Render(m.commandErr))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(m.helpStyle.Render("Enter: run | Esc: cancel"))
+
+	return b.String()
+}
+
+// viewHistory renders the :history overlay: the most recent ops, newest
+// first, with a ">" marking the one undo would currently unapply.
+func (m model) viewHistory() string {
+	var b strings.Builder
+
+	b.WriteString(m.titleStyle.Render("Op History"))
+	b.WriteString("\n\n")
+
+	ops := m.history.recent(20)
+	if len(ops) == 0 {
+		b.WriteString(m.helpStyle.Render("(no operations yet)"))
+		b.WriteString("\n")
+	}
+	for i, op := range ops {
+		marker := "  "
+		if i == 0 {
+			marker = "> "
+		}
+		line := fmt.Sprintf("%s[%s] %s  %s", marker, op.Time().Format("15:04:05"), op.Kind(), op.Summary())
+		b.WriteString(m.normalStyle.Render(line))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.helpStyle.Render("u: undo | ctrl+r: redo | any other key: close"))
+
+	return b.String()
+}
+
+// viewAIReview renders the :review overlay: every pending AI suggestion,
+// sorted by confidence descending, with a checkbox reflecting
+// reviewSelected and the current "["/"]" threshold.
+func (m model) viewAIReview() string {
+	var b strings.Builder
+
+	b.WriteString(m.titleStyle.Render("Review AI Suggestions"))
+	b.WriteString("\n\n")
+	b.WriteString(m.progressStyle.Render(fmt.Sprintf("Accept threshold: %.2f", m.reviewThreshold)))
+	b.WriteString("\n\n")
+
+	if len(m.reviewOrder) == 0 {
+		b.WriteString(m.helpStyle.Render("(no pending AI suggestions)"))
+		b.WriteString("\n")
+	}
+	for i, scanned := range m.reviewOrder {
+		checkbox := "[ ]"
+		if m.reviewSelected[scanned] {
+			checkbox = "[x]"
+		}
+		prefix := "  "
+		style := m.normalStyle
+		if i == m.reviewCursor {
+			prefix = "> "
+			style = m.selectedStyle
+		}
+		line := fmt.Sprintf("%s%s %s → %s (%.2f)", prefix, checkbox, scanned, m.aiSuggestions[scanned], m.aiConfidence[scanned])
+		b.WriteString(style.Render(line))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.helpStyle.Render("↑↓: navigate | space: toggle | [/]: threshold | A: check ≥ threshold | R: drop checked | Enter: commit | Esc: back"))
 
 	return b.String()
 }
@@ -639,12 +1291,20 @@ func (m model) viewSelectTarget() string {
 	b.WriteString(m.titleStyle.Render(title))
 	b.WriteString("\n\n")
 
+	if m.targetFilterActive {
+		b.WriteString(m.targetFilterInput.View())
+		b.WriteString("\n\n")
+	}
+
 	// Page info
 	totalPages := int(math.Ceil(float64(len(m.targetColumns)) / float64(m.targetPerPage)))
 	if totalPages == 0 {
 		totalPages = 1
 	}
 	pageInfo := fmt.Sprintf("Page %d/%d", m.targetPage+1, totalPages)
+	if m.targetFilterScores != nil {
+		pageInfo += fmt.Sprintf(" (%d matches)", len(m.targetColumns))
+	}
 	b.WriteString(m.helpStyle.Render(pageInfo))
 	b.WriteString("\n\n")
 
@@ -670,11 +1330,14 @@ func (m model) viewSelectTarget() string {
 		}
 
 		// Highlight AI suggestions
+		displayText := column
 		if aiTarget, hasAI := m.aiSuggestions[m.currentScanned]; hasAI && column == aiTarget {
-			displayText := column + " (AI suggestion)"
-			b.WriteString(style.Render(prefix + displayText))
-		} else {
-			b.WriteString(style.Render(prefix + column))
+			displayText += " (AI suggestion)"
+		}
+		b.WriteString(style.Render(prefix + displayText))
+
+		if score, scored := m.targetFilterScores[column]; scored {
+			b.WriteString(m.helpStyle.Render(fmt.Sprintf("  %.1f", score)))
 		}
 		b.WriteString("\n")
 	}
@@ -682,7 +1345,10 @@ func (m model) viewSelectTarget() string {
 	b.WriteString("\n")
 
 	// Help
-	help := "↑↓: navigate | ←→: prev/next page | Enter: select | Esc: back | q: quit"
+	help := "↑↓: navigate | ←→: prev/next page | Enter: select | /: filter | u: undo | ctrl+r: redo | Esc: back | q: quit"
+	if m.targetFilterActive {
+		help = "↑↓: navigate | Enter: select top hit | Esc: clear filter | ctrl+c: quit"
+	}
 	b.WriteString(m.helpStyle.Render(help))
 
 	return b.String()
@@ -722,10 +1388,40 @@ func (m model) viewAILoading() string {
 	b.WriteString("\n\n")
 
 	// Loading message
-	loading := "Generating AI mapping suggestions..."
+	loading := fmt.Sprintf("Generating AI mapping suggestions via %s...", m.aiProviderName)
 	b.WriteString(m.loadingStyle.Render(loading))
 	b.WriteString("\n\n")
 
+	// Progress bar, scaled to the terminal width, for providers that
+	// stream per-mapping progress. Non-streaming providers never send an
+	// aiProgressMsg, so aiTotal stays at the unmapped-column count but
+	// aiSuggestionCount stays 0 until the whole batch lands at once.
+	if m.aiTotal > 0 {
+		barWidth := m.width - 12
+		if barWidth < 10 {
+			barWidth = 10
+		}
+		filled := barWidth * m.aiSuggestionCount / m.aiTotal
+		if filled > barWidth {
+			filled = barWidth
+		}
+		bar := strings.Repeat("█", filled) + strings.Repeat("░", barWidth-filled)
+		b.WriteString(m.progressStyle.Render(fmt.Sprintf("[%s] %d/%d", bar, m.aiSuggestionCount, m.aiTotal)))
+		b.WriteString("\n")
+	}
+
+	if m.aiLatestColumn != "" {
+		b.WriteString(m.helpStyle.Render(fmt.Sprintf("latest: %s", m.aiLatestColumn)))
+		b.WriteString("\n")
+	}
+
+	if !m.aiStartTime.IsZero() {
+		b.WriteString(m.helpStyle.Render(fmt.Sprintf("elapsed: %s", time.Since(m.aiStartTime).Round(time.Second))))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+
 	// Help
 	help := "Esc: cancel | q: quit"
 	b.WriteString(m.helpStyle.Render(help))
@@ -733,8 +1429,11 @@ func (m model) viewAILoading() string {
 	return b.String()
 }
 
-// RunMappingTUI starts the interactive mapping interface
-func RunMappingTUI(scannedColumnsFile, targetColumnsFile, outputMappingFile string, uiConfig UIConfig) error {
+// RunMappingTUI starts the interactive mapping interface. providerName
+// selects the AI provider pre-selected on launch (DefaultProviderName if
+// empty); providerConfig is passed through to whichever provider the user
+// ends up picking with "p".
+func RunMappingTUI(scannedColumnsFile, targetColumnsFile, outputMappingFile string, uiConfig UIConfig, providerConfig ProviderConfig, providerName string) error {
 	scannedColumns, err := ReadColumnsFromFile(scannedColumnsFile)
 	if err != nil {
 		return fmt.Errorf("failed to read scanned columns: %v", err)
@@ -755,7 +1454,7 @@ func RunMappingTUI(scannedColumnsFile, targetColumnsFile, outputMappingFile stri
 	}
 
 	// Initialize the TUI model with config
-	m := initialModel(scannedColumns, targetColumns, uiConfig)
+	m := initialModel(scannedColumns, targetColumns, uiConfig, providerConfig, providerName)
 
 	// Load existing mappings if the file exists
 	if existingConfig, err := LoadFromFile(outputMappingFile); err == nil {
@@ -765,10 +1464,8 @@ func RunMappingTUI(scannedColumnsFile, targetColumnsFile, outputMappingFile stri
 		for _, mapping := range existingConfig.Mappings {
 			if mapping.IsIgnored {
 				m.ignored[mapping.ScannedColumn] = true
-				m.mapped++
 			} else if mapping.TargetColumn != "" {
 				m.mappings[mapping.ScannedColumn] = mapping.TargetColumn
-				m.mapped++
 			}
 		}
 
@@ -797,7 +1494,6 @@ func RunMappingTUI(scannedColumnsFile, targetColumnsFile, outputMappingFile stri
 		// Check if there's an exact match in target columns
 		if targetColumnsSet[scanned] {
 			m.mappings[scanned] = scanned
-			m.mapped++
 			autoMappedCount++
 		}
 	}
@@ -806,13 +1502,9 @@ func RunMappingTUI(scannedColumnsFile, targetColumnsFile, outputMappingFile stri
 		fmt.Printf("🔗 Auto-mapped %d exact matches\n", autoMappedCount)
 	}
 
-	// Show API key status
-	apiKey := GetGeminiAPIKey()
-	if apiKey != "" {
-		fmt.Printf("AI mapping available (press 'a' to generate suggestions)\n")
-	} else {
-		fmt.Printf("ℹ️  Set GEMINI_API_KEY to enable AI mapping (press 'a')\n")
-	}
+	// Show AI provider status
+	fmt.Printf("AI mapping available via %s (press 'a' to generate suggestions, 'p' to pick a different provider)\n", m.aiProviderName)
+	fmt.Printf("   Available providers: %s\n", strings.Join(ProviderNames(), ", "))
 
 	// Move to first unmapped column
 	m.moveToNextUnmapped()
@@ -4,9 +4,9 @@ import (
 	"bufio"
 	"encoding/json"
 	"fmt"
-	"os"
-	"sheetFmt/internal/excel"
+	"io"
 	"sheetFmt/internal/logger"
+	"sheetFmt/internal/tabular"
 	"strings"
 )
 
@@ -15,6 +15,10 @@ type ColumnMapping struct {
 	ScannedColumn string `json:"scanned_column"`
 	TargetColumn  string `json:"target_column"`
 	IsIgnored     bool   `json:"is_ignored"`
+	// Confidence is the similarity score SuggestMappings assigned this
+	// mapping (0-1), zero for mappings entered by hand. Lets a reviewer
+	// sort a suggested mapping file by how much to trust each row.
+	Confidence float64 `json:"confidence,omitempty"`
 }
 
 // MappingConfig holds all column mappings
@@ -22,14 +26,21 @@ type MappingConfig struct {
 	Mappings []ColumnMapping `json:"mappings"`
 }
 
-// SaveToFile saves the mapping configuration to a JSON file
+// SaveToFile saves the mapping configuration to a JSON file. It's a thin
+// shim over SaveToFileFS using the real filesystem.
 func (mc *MappingConfig) SaveToFile(filepath string) error {
+	return mc.SaveToFileFS(OSFS{}, filepath)
+}
+
+// SaveToFileFS is SaveToFile with the write routed through fsys instead of
+// os.WriteFile directly.
+func (mc *MappingConfig) SaveToFileFS(fsys FS, filepath string) error {
 	file, err := json.MarshalIndent(mc, "", "  ")
 	if err != nil {
 		return err
 	}
 
-	err = writeToFile(filepath, file)
+	err = writeToFileFS(fsys, filepath, file)
 	if err != nil {
 		return err
 	}
@@ -38,9 +49,16 @@ func (mc *MappingConfig) SaveToFile(filepath string) error {
 	return nil
 }
 
-// LoadFromFile loads mapping configuration from a JSON file
+// LoadFromFile loads mapping configuration from a JSON file. It's a thin
+// shim over LoadFromFileFS using the real filesystem.
 func LoadFromFile(filepath string) (*MappingConfig, error) {
-	data, err := readFromFile(filepath)
+	return LoadFromFileFS(OSFS{}, filepath)
+}
+
+// LoadFromFileFS is LoadFromFile with the read routed through fsys instead
+// of os.ReadFile directly.
+func LoadFromFileFS(fsys FS, filepath string) (*MappingConfig, error) {
+	data, err := readFromFileFS(fsys, filepath)
 	if err != nil {
 		return nil, err
 	}
@@ -55,9 +73,16 @@ func LoadFromFile(filepath string) (*MappingConfig, error) {
 	return &config, nil
 }
 
-// ReadColumnsFromFile reads column names from a text file (one per line)
+// ReadColumnsFromFile reads column names from a text file (one per line).
+// It's a thin shim over ReadColumnsFromFileFS using the real filesystem.
 func ReadColumnsFromFile(filepath string) ([]string, error) {
-	file, err := os.Open(filepath)
+	return ReadColumnsFromFileFS(OSFS{}, filepath)
+}
+
+// ReadColumnsFromFileFS is ReadColumnsFromFile with the read routed through
+// fsys instead of os.Open directly.
+func ReadColumnsFromFileFS(fsys FS, filepath string) ([]string, error) {
+	file, err := fsys.Open(filepath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open file %s: %v", filepath, err)
 	}
@@ -81,9 +106,17 @@ func ReadColumnsFromFile(filepath string) ([]string, error) {
 	return columns, nil
 }
 
-// CreateDefaultTargetColumnsFile creates a sample target_columns file if it doesn't exist
+// CreateDefaultTargetColumnsFile creates a sample target_columns file if it
+// doesn't exist. It's a thin shim over CreateDefaultTargetColumnsFileFS
+// using the real filesystem.
 func CreateDefaultTargetColumnsFile(filepath string) error {
-	if _, err := os.Stat(filepath); err == nil {
+	return CreateDefaultTargetColumnsFileFS(OSFS{}, filepath)
+}
+
+// CreateDefaultTargetColumnsFileFS is CreateDefaultTargetColumnsFile with
+// the existence check and write routed through fsys instead of os directly.
+func CreateDefaultTargetColumnsFileFS(fsys FS, filepath string) error {
+	if _, err := fsys.Stat(filepath); err == nil {
 		return nil // File already exists
 	}
 
@@ -98,7 +131,7 @@ func CreateDefaultTargetColumnsFile(filepath string) error {
 		"Description",
 	}
 
-	file, err := os.Create(filepath)
+	file, err := fsys.Create(filepath)
 	if err != nil {
 		return fmt.Errorf("failed to create target columns file: %v", err)
 	}
@@ -118,25 +151,37 @@ func CreateDefaultTargetColumnsFile(filepath string) error {
 	return nil
 }
 
-// AppendTargetFormatHeadersToFile reads headers from target format file and appends unique ones to target_columns file
+// AppendTargetFormatHeadersToFile reads headers from the target format file
+// (XLSX or CSV/TSV) and appends unique ones to target_columns file. It's a
+// thin shim over AppendTargetFormatHeadersToFileFS using the real
+// filesystem.
 func AppendTargetFormatHeadersToFile(targetFormatFile, targetSheet, targetColumnsFile string) error {
-	// Open the target format file
-	editor, err := excel.OpenFile(targetFormatFile)
+	return AppendTargetFormatHeadersToFileFS(OSFS{}, targetFormatFile, targetSheet, targetColumnsFile)
+}
+
+// AppendTargetFormatHeadersToFileFS is AppendTargetFormatHeadersToFile with
+// the target_columns read/write routed through fsys instead of os directly.
+// Reading the target format file itself still goes through
+// tabular.OpenReader, which isn't FS-aware yet.
+func AppendTargetFormatHeadersToFileFS(fsys FS, targetFormatFile, targetSheet, targetColumnsFile string) error {
+	// Open the target format file through the tabular abstraction so this
+	// works whether the target is an XLSX template or a CSV/TSV file
+	reader, err := tabular.OpenReader(targetFormatFile, targetSheet)
 	if err != nil {
 		return fmt.Errorf("failed to open target format file: %v", err)
 	}
-	defer editor.Close()
+	defer reader.Close()
 
 	// Get headers from the target format file
-	headers, err := editor.GetColumnHeaders(targetSheet)
+	headers, err := reader.Headers()
 	if err != nil {
 		return fmt.Errorf("failed to get headers from target format file: %v", err)
 	}
 
 	// Read existing target columns (if file exists)
 	var existingColumns []string
-	if _, err := os.Stat(targetColumnsFile); err == nil {
-		existingColumns, err = ReadColumnsFromFile(targetColumnsFile)
+	if _, err := fsys.Stat(targetColumnsFile); err == nil {
+		existingColumns, err = ReadColumnsFromFileFS(fsys, targetColumnsFile)
 		if err != nil {
 			return fmt.Errorf("failed to read existing target columns: %v", err)
 		}
@@ -165,7 +210,7 @@ func AppendTargetFormatHeadersToFile(targetFormatFile, targetSheet, targetColumn
 	allColumns := append(existingColumns, newColumns...)
 
 	// Write back to target_columns file
-	err = writeColumnsToFile(targetColumnsFile, allColumns)
+	err = writeColumnsToFileFS(fsys, targetColumnsFile, allColumns)
 	if err != nil {
 		return fmt.Errorf("failed to write updated target columns: %v", err)
 	}
@@ -178,9 +223,17 @@ func AppendTargetFormatHeadersToFile(targetFormatFile, targetSheet, targetColumn
 	return nil
 }
 
-// writeColumnsToFile writes column names to a plain text file (helper function)
+// writeColumnsToFile writes column names to a plain text file (helper
+// function). It's a thin shim over writeColumnsToFileFS using the real
+// filesystem.
 func writeColumnsToFile(filename string, columns []string) error {
-	file, err := os.Create(filename)
+	return writeColumnsToFileFS(OSFS{}, filename, columns)
+}
+
+// writeColumnsToFileFS is writeColumnsToFile with the write routed through
+// fsys instead of os.Create directly.
+func writeColumnsToFileFS(fsys FS, filename string, columns []string) error {
+	file, err := fsys.Create(filename)
 	if err != nil {
 		return fmt.Errorf("failed to create file: %v", err)
 	}
@@ -200,10 +253,21 @@ func writeColumnsToFile(filename string, columns []string) error {
 }
 
 // Helper functions
-func writeToFile(filepath string, data []byte) error {
-	return os.WriteFile(filepath, data, 0644)
+func writeToFileFS(fsys FS, filepath string, data []byte) error {
+	file, err := fsys.Create(filepath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.Write(data)
+	return err
 }
 
-func readFromFile(filepath string) ([]byte, error) {
-	return os.ReadFile(filepath)
+func readFromFileFS(fsys FS, filepath string) ([]byte, error) {
+	file, err := fsys.Open(filepath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(file)
 }
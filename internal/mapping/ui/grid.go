@@ -0,0 +1,269 @@
+// Package ui provides a small declarative grid layout primitive for the
+// mapping TUIs, modeled after aerc's DimSpec grid: rows and columns are
+// declared up front as either an exact character count or a weight that
+// shares out whatever space is left, children are placed at a (row, col)
+// with an optional span, and the whole thing recomputes on every
+// tea.WindowSizeMsg instead of the views doing their own division.
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// SizeMode selects how a DimSpec's Value is interpreted.
+type SizeMode int
+
+const (
+	// SizeExact reserves exactly Value cells for the track.
+	SizeExact SizeMode = iota
+	// SizeWeight shares the space left over after exact tracks are
+	// satisfied, proportionally to Value among the other weighted tracks.
+	SizeWeight
+)
+
+// DimSpec declares the size of one grid row or column.
+type DimSpec struct {
+	Mode  SizeMode
+	Value int
+}
+
+// Exact declares a track that always takes exactly n cells.
+func Exact(n int) DimSpec { return DimSpec{Mode: SizeExact, Value: n} }
+
+// Weight declares a track that shares the remaining space proportionally
+// to w among the grid's other weighted tracks.
+func Weight(w int) DimSpec { return DimSpec{Mode: SizeWeight, Value: w} }
+
+// Renderer is anything that can draw itself into a fixed-size rectangle.
+// Text wraps a plain string for the common case.
+type Renderer interface {
+	Render(width, height int) string
+}
+
+// Text is a Renderer over a static string, padded/truncated to fit the
+// cell the grid negotiates for it.
+type Text string
+
+func (t Text) Render(width, height int) string {
+	lines := strings.Split(string(t), "\n")
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	style := lipgloss.NewStyle().Width(width).MaxWidth(width).MaxHeight(height)
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	return style.Render(strings.Join(lines, "\n"))
+}
+
+// Rect is a computed, absolute placement in grid cells.
+type Rect struct {
+	X, Y, Width, Height int
+}
+
+// Grid lays out a fixed set of row and column tracks and places children
+// at a given (row, col), optionally spanning further rows/columns.
+type Grid struct {
+	rows []DimSpec
+	cols []DimSpec
+
+	width, height int
+
+	children []*placement
+}
+
+type placement struct {
+	row, col         int
+	rowSpan, colSpan int
+	content          Renderer
+}
+
+// Placement is a builder handle returned by Add, letting callers chain
+// .At(row, col) and .Span(rowSpan, colSpan).
+type Placement struct {
+	p *placement
+}
+
+// At positions the child at the given zero-based row/column.
+func (pl *Placement) At(row, col int) *Placement {
+	pl.p.row, pl.p.col = row, col
+	return pl
+}
+
+// Span extends the child across additional rows/columns (default 1x1).
+func (pl *Placement) Span(rowSpan, colSpan int) *Placement {
+	pl.p.rowSpan, pl.p.colSpan = rowSpan, colSpan
+	return pl
+}
+
+// NewGrid declares a grid with the given row and column tracks.
+func NewGrid(rows, cols []DimSpec) *Grid {
+	return &Grid{rows: rows, cols: cols}
+}
+
+// SetSize sets the overall pixel (character cell) size the grid renders
+// into; call this from a tea.WindowSizeMsg handler to invalidate and
+// recompute every child's rectangle.
+func (g *Grid) SetSize(width, height int) {
+	g.width, g.height = width, height
+}
+
+// Add registers a child renderer at (0, 0) with a 1x1 span by default;
+// chain .At/.Span on the returned Placement to adjust it.
+func (g *Grid) Add(content Renderer) *Placement {
+	p := &placement{rowSpan: 1, colSpan: 1, content: content}
+	g.children = append(g.children, p)
+	return &Placement{p: p}
+}
+
+// Reset discards all placed children so the grid can be rebuilt for the
+// next frame (rows/cols/size are kept).
+func (g *Grid) Reset() {
+	g.children = nil
+}
+
+// trackSizes distributes total cells across specs: exact tracks get their
+// declared size (clamped so they never exceed what's left), and whatever
+// remains is shared across weighted tracks proportionally to their weight,
+// with any leftover rounding given to the last weighted track.
+func trackSizes(specs []DimSpec, total int) []int {
+	sizes := make([]int, len(specs))
+
+	remaining := total
+	totalWeight := 0
+	for i, s := range specs {
+		if s.Mode == SizeExact {
+			size := s.Value
+			if size > remaining {
+				size = remaining
+			}
+			if size < 0 {
+				size = 0
+			}
+			sizes[i] = size
+			remaining -= size
+		} else {
+			totalWeight += s.Value
+		}
+	}
+
+	if totalWeight == 0 || remaining <= 0 {
+		return sizes
+	}
+
+	assigned := 0
+	lastWeighted := -1
+	for i, s := range specs {
+		if s.Mode != SizeWeight {
+			continue
+		}
+		lastWeighted = i
+		share := remaining * s.Value / totalWeight
+		sizes[i] = share
+		assigned += share
+	}
+	if lastWeighted >= 0 {
+		sizes[lastWeighted] += remaining - assigned
+	}
+
+	return sizes
+}
+
+// ColWidths returns the negotiated width of each column track.
+func (g *Grid) ColWidths() []int { return trackSizes(g.cols, g.width) }
+
+// RowHeights returns the negotiated height of each row track.
+func (g *Grid) RowHeights() []int { return trackSizes(g.rows, g.height) }
+
+// CellRect returns the computed rectangle for a cell spanning rowSpan
+// rows and colSpan columns starting at (row, col).
+func (g *Grid) CellRect(row, col, rowSpan, colSpan int) Rect {
+	rowSizes := trackSizes(g.rows, g.height)
+	colSizes := trackSizes(g.cols, g.width)
+
+	var x, y, w, h int
+	for i := 0; i < col && i < len(colSizes); i++ {
+		x += colSizes[i]
+	}
+	for i := 0; i < row && i < len(rowSizes); i++ {
+		y += rowSizes[i]
+	}
+	for i := col; i < col+colSpan && i < len(colSizes); i++ {
+		w += colSizes[i]
+	}
+	for i := row; i < row+rowSpan && i < len(rowSizes); i++ {
+		h += rowSizes[i]
+	}
+
+	return Rect{X: x, Y: y, Width: w, Height: h}
+}
+
+// Render composes every placed child into its negotiated rectangle and
+// returns the finished grid as a single multi-line string sized to
+// width x height.
+func (g *Grid) Render() string {
+	canvas := make([]string, g.height)
+	for i := range canvas {
+		canvas[i] = strings.Repeat(" ", max(g.width, 0))
+	}
+
+	for _, p := range g.children {
+		rect := g.CellRect(p.row, p.col, p.rowSpan, p.colSpan)
+		if rect.Width <= 0 || rect.Height <= 0 {
+			continue
+		}
+
+		content := p.content.Render(rect.Width, rect.Height)
+		lines := strings.Split(content, "\n")
+
+		for i := 0; i < rect.Height; i++ {
+			canvasRow := rect.Y + i
+			if canvasRow < 0 || canvasRow >= len(canvas) {
+				continue
+			}
+			line := ""
+			if i < len(lines) {
+				line = lines[i]
+			}
+			canvas[canvasRow] = overlay(canvas[canvasRow], line, rect.X, rect.Width)
+		}
+	}
+
+	return strings.Join(canvas, "\n")
+}
+
+// overlay writes replacement (padded/truncated to width) over base
+// starting at display column x. Both strings may carry ANSI/SGR escape
+// sequences (every styled cell does, via StyledText), so splicing is
+// done by measuring and cutting on cell width rather than byte or rune
+// offsets, which would otherwise slice escape codes in half.
+func overlay(base, replacement string, x, width int) string {
+	replWidth := lipgloss.Width(replacement)
+	if replWidth > width {
+		replacement = ansi.Truncate(replacement, width, "")
+		replWidth = lipgloss.Width(replacement)
+	}
+	if replWidth < width {
+		replacement += strings.Repeat(" ", width-replWidth)
+	}
+
+	baseWidth := lipgloss.Width(base)
+	if baseWidth < x+width {
+		base += strings.Repeat(" ", x+width-baseWidth)
+		baseWidth = x + width
+	}
+
+	left := ansi.Cut(base, 0, x)
+	right := ansi.Cut(base, x+width, baseWidth)
+	return left + replacement + right
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
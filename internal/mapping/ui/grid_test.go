@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// TestGridRenderPreservesStyledText guards against overlay/Render
+// splicing ANSI/SGR escape sequences as if they were visible
+// characters: every real terminal (TrueColor, ANSI256, even ANSI)
+// renders StyledText cells with color codes, and the grid must still
+// composite the underlying text intact.
+func TestGridRenderPreservesStyledText(t *testing.T) {
+	prev := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	defer lipgloss.SetColorProfile(prev)
+
+	g := NewGrid([]DimSpec{Exact(1)}, []DimSpec{Weight(1), Weight(1)})
+	g.SetSize(20, 1)
+	g.Add(StyledText{Content: "left", Style: lipgloss.NewStyle().Foreground(lipgloss.Color("205"))}).At(0, 0)
+	g.Add(StyledText{Content: "right", Style: lipgloss.NewStyle().Foreground(lipgloss.Color("33"))}).At(0, 1)
+
+	out := g.Render()
+	plain := ansiStrip(out)
+
+	if !strings.Contains(plain, "left") {
+		t.Errorf("rendered grid lost styled cell %q, got stripped output %q", "left", plain)
+	}
+	if !strings.Contains(plain, "right") {
+		t.Errorf("rendered grid lost styled cell %q, got stripped output %q", "right", plain)
+	}
+}
+
+// ansiStrip removes SGR escape sequences so assertions can check the
+// visible text without depending on a specific terminal profile.
+func ansiStrip(s string) string {
+	var b strings.Builder
+	inEscape := false
+	for _, r := range s {
+		if inEscape {
+			if r == 'm' {
+				inEscape = false
+			}
+			continue
+		}
+		if r == '\x1b' {
+			inEscape = true
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
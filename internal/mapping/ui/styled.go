@@ -0,0 +1,27 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// StyledText is a Renderer over a plain string rendered through a
+// lipgloss.Style, for grid cells that need color/emphasis beyond what
+// Text's bare padding gives (selection highlight, mapped/ignored/AI
+// states, and so on).
+type StyledText struct {
+	Content string
+	Style   lipgloss.Style
+}
+
+func (s StyledText) Render(width, height int) string {
+	lines := strings.Split(s.Content, "\n")
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	return s.Style.Width(width).MaxWidth(width).MaxHeight(height).Render(strings.Join(lines, "\n"))
+}
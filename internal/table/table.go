@@ -0,0 +1,320 @@
+// Package table provides a virtualized, scrollable grid for rendering
+// tabular data (spreadsheet rows, CSV rows) inside a Bubble Tea program.
+// It only renders the rows currently in view and negotiates column widths
+// against the available terminal width, so it stays responsive for sheets
+// with many thousands of rows and/or columns.
+package table
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	minColWidth = 8
+	maxColWidth = 32
+	colPadding  = 1 // extra spacing rendered between columns
+)
+
+// Model renders headers plus a window of data rows with a moveable cell
+// cursor. Callers drive it with MoveCursor/GotoRow/SetRows and read the
+// highlighted cell back out with CursorHeader/CursorValue.
+type Model struct {
+	headers []string
+	rows    [][]string
+
+	cursorRow int
+	cursorCol int
+
+	topRow  int // index of the first visible data row
+	leftCol int // index of the first visible column
+
+	width  int
+	height int
+
+	colWidths []int
+
+	headerStyle   lipgloss.Style
+	cellStyle     lipgloss.Style
+	selectedStyle lipgloss.Style
+}
+
+// NewModel builds a table over headers and rows. Column widths and the
+// viewport are left at zero size until SetSize is called.
+func NewModel(headers []string, rows [][]string) Model {
+	return Model{
+		headers: headers,
+		rows:    rows,
+		headerStyle: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("205")),
+		cellStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("252")),
+		selectedStyle: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("170")).
+			Background(lipgloss.Color("235")),
+	}
+}
+
+// SetSize updates the viewport dimensions and renegotiates column widths.
+func (m *Model) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.negotiateColumnWidths()
+	m.ensureCursorVisible()
+}
+
+// SetRows replaces the data rows shown (used by :filter) and clamps the
+// cursor and viewport back into range.
+func (m *Model) SetRows(rows [][]string) {
+	m.rows = rows
+	if m.cursorRow >= len(rows) {
+		m.cursorRow = len(rows) - 1
+	}
+	if m.cursorRow < 0 {
+		m.cursorRow = 0
+	}
+	m.topRow = 0
+	m.ensureCursorVisible()
+}
+
+// negotiateColumnWidths sizes each column to fit its longest value, capped
+// to [minColWidth, maxColWidth], then, while the row is still wider than
+// the viewport, takes one cell at a time from each column still above
+// minColWidth in turn.
+func (m *Model) negotiateColumnWidths() {
+	if len(m.headers) == 0 {
+		m.colWidths = nil
+		return
+	}
+
+	widths := make([]int, len(m.headers))
+	for i, h := range m.headers {
+		widths[i] = clampWidth(utf8.RuneCountInString(h))
+	}
+	for _, row := range m.rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				break
+			}
+			if w := clampWidth(utf8.RuneCountInString(cell)); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+
+	total := 0
+	for _, w := range widths {
+		total += w + colPadding
+	}
+
+	if m.width > 0 && total > m.width {
+		// Shrink the columns one cell at a time, round-robin, until the
+		// row fits, never going below minColWidth.
+		for total > m.width {
+			shrunkAny := false
+			for i := range widths {
+				if widths[i] > minColWidth {
+					widths[i]--
+					total--
+					shrunkAny = true
+					if total <= m.width {
+						break
+					}
+				}
+			}
+			if !shrunkAny {
+				break
+			}
+		}
+	}
+
+	m.colWidths = widths
+}
+
+func clampWidth(n int) int {
+	if n < minColWidth {
+		return minColWidth
+	}
+	if n > maxColWidth {
+		return maxColWidth
+	}
+	return n
+}
+
+// visibleRows is how many data rows fit below the header line.
+func (m Model) visibleRows() int {
+	h := m.height - 1 // header row
+	if h < 1 {
+		return 1
+	}
+	return h
+}
+
+// MoveCursor shifts the cell cursor by (dRow, dCol), clamping to the data
+// bounds and scrolling the viewport to keep the cursor visible.
+func (m *Model) MoveCursor(dRow, dCol int) {
+	row := m.cursorRow + dRow
+	if row < 0 {
+		row = 0
+	}
+	if row > len(m.rows)-1 {
+		row = len(m.rows) - 1
+	}
+	if row < 0 {
+		row = 0
+	}
+
+	col := m.cursorCol + dCol
+	if col < 0 {
+		col = 0
+	}
+	if col > len(m.headers)-1 {
+		col = len(m.headers) - 1
+	}
+	if col < 0 {
+		col = 0
+	}
+
+	m.cursorRow = row
+	m.cursorCol = col
+	m.ensureCursorVisible()
+}
+
+func (m *Model) ensureCursorVisible() {
+	visible := m.visibleRows()
+	if m.cursorRow < m.topRow {
+		m.topRow = m.cursorRow
+	} else if m.cursorRow >= m.topRow+visible {
+		m.topRow = m.cursorRow - visible + 1
+	}
+	if m.topRow < 0 {
+		m.topRow = 0
+	}
+}
+
+// GotoRow jumps the cursor to the given zero-based row index, reporting
+// false (and leaving the cursor unchanged) if it's out of range.
+func (m *Model) GotoRow(row int) bool {
+	if row < 0 || row >= len(m.rows) {
+		return false
+	}
+	m.cursorRow = row
+	m.ensureCursorVisible()
+	return true
+}
+
+// CursorHeader returns the header of the column under the cursor.
+func (m Model) CursorHeader() string {
+	if m.cursorCol < 0 || m.cursorCol >= len(m.headers) {
+		return ""
+	}
+	return m.headers[m.cursorCol]
+}
+
+// CursorValue returns the value of the cell under the cursor.
+func (m Model) CursorValue() string {
+	if m.cursorRow < 0 || m.cursorRow >= len(m.rows) {
+		return ""
+	}
+	row := m.rows[m.cursorRow]
+	if m.cursorCol < 0 || m.cursorCol >= len(row) {
+		return ""
+	}
+	return row[m.cursorCol]
+}
+
+// CursorRow and CursorCol expose the current cell position.
+func (m Model) CursorRow() int { return m.cursorRow }
+func (m Model) CursorCol() int { return m.cursorCol }
+
+// RowCount and ColCount report the current (possibly filtered) dimensions.
+func (m Model) RowCount() int { return len(m.rows) }
+func (m Model) ColCount() int { return len(m.headers) }
+
+// Headers returns the column headers.
+func (m Model) Headers() []string { return m.headers }
+
+// Row returns the data row at the given index, or nil if out of range.
+func (m Model) Row(i int) []string {
+	if i < 0 || i >= len(m.rows) {
+		return nil
+	}
+	return m.rows[i]
+}
+
+// Find scans forward from just after the cursor for a row containing
+// substr in any column, wrapping around once. It returns the matching row
+// index and true, or -1 and false if nothing matched.
+func (m Model) Find(substr string) (int, bool) {
+	if substr == "" || len(m.rows) == 0 {
+		return -1, false
+	}
+	n := len(m.rows)
+	for offset := 1; offset <= n; offset++ {
+		idx := (m.cursorRow + offset) % n
+		for _, cell := range m.rows[idx] {
+			if strings.Contains(strings.ToLower(cell), strings.ToLower(substr)) {
+				return idx, true
+			}
+		}
+	}
+	return -1, false
+}
+
+// View renders the header row plus the currently visible window of data
+// rows, highlighting the cursor cell.
+func (m Model) View() string {
+	if len(m.headers) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(m.renderRow(m.headers, -1, m.headerStyle))
+	b.WriteString("\n")
+
+	visible := m.visibleRows()
+	end := m.topRow + visible
+	if end > len(m.rows) {
+		end = len(m.rows)
+	}
+
+	for i := m.topRow; i < end; i++ {
+		b.WriteString(m.renderRow(m.rows[i], i, m.cellStyle))
+		if i != end-1 {
+			b.WriteString("\n")
+		}
+	}
+
+	return b.String()
+}
+
+func (m Model) renderRow(cells []string, rowIdx int, base lipgloss.Style) string {
+	var parts []string
+	for i, w := range m.colWidths {
+		var text string
+		if i < len(cells) {
+			text = cells[i]
+		}
+		if utf8.RuneCountInString(text) > w {
+			runes := []rune(text)
+			if w > 1 {
+				text = string(runes[:w-1]) + "…"
+			} else {
+				text = string(runes[:w])
+			}
+		}
+
+		style := base
+		if rowIdx == m.cursorRow && i == m.cursorCol {
+			style = m.selectedStyle
+		}
+
+		parts = append(parts, style.Render(fmt.Sprintf("%-*s", w, text)))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,131 @@
+package tabular
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+)
+
+// utf8BOM is the byte sequence Excel prepends to CSV exports.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// csvReader adapts encoding/csv to the Reader interface.
+type csvReader struct {
+	file    *os.File
+	reader  *csv.Reader
+	headers []string
+}
+
+func newCSVReader(path string, opts CSVOptions) (Reader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %v", path, err)
+	}
+
+	var src io.Reader = file
+	if opts.StripBOM {
+		src = stripBOMReader(file)
+	}
+
+	reader := csv.NewReader(bufio.NewReader(src))
+	reader.Comma = opts.Delimiter
+	reader.FieldsPerRecord = -1 // tolerate ragged rows
+
+	r := &csvReader{file: file, reader: reader}
+	headers, err := reader.Read()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to read header row from %s: %v", path, err)
+	}
+	r.headers = headers
+	return r, nil
+}
+
+func (r *csvReader) Headers() ([]string, error) {
+	return r.headers, nil
+}
+
+func (r *csvReader) Rows() (RowIter, error) {
+	return &csvRowIter{reader: r.reader}, nil
+}
+
+func (r *csvReader) Close() error {
+	return r.file.Close()
+}
+
+type csvRowIter struct {
+	reader  *csv.Reader
+	current []string
+	lastErr error
+}
+
+func (it *csvRowIter) Next() bool {
+	record, err := it.reader.Read()
+	if err != nil {
+		if err != io.EOF {
+			it.lastErr = err
+		}
+		return false
+	}
+	it.current = record
+	return true
+}
+
+func (it *csvRowIter) Columns() ([]string, error) {
+	return it.current, nil
+}
+
+func (it *csvRowIter) Err() error {
+	return it.lastErr
+}
+
+func (it *csvRowIter) Close() error {
+	return nil
+}
+
+// csvWriter adapts encoding/csv to the Writer interface.
+type csvWriter struct {
+	file   *os.File
+	writer *csv.Writer
+}
+
+func newCSVWriter(path string, opts CSVOptions) (Writer, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %v", path, err)
+	}
+
+	writer := csv.NewWriter(file)
+	writer.Comma = opts.Delimiter
+
+	return &csvWriter{file: file, writer: writer}, nil
+}
+
+func (w *csvWriter) WriteHeader(headers []string) error {
+	return w.writer.Write(headers)
+}
+
+func (w *csvWriter) WriteRow(row []string) error {
+	return w.writer.Write(row)
+}
+
+func (w *csvWriter) Close() error {
+	w.writer.Flush()
+	if err := w.writer.Error(); err != nil {
+		w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
+
+// stripBOMReader wraps r and discards a leading UTF-8 BOM if present.
+func stripBOMReader(r io.Reader) io.Reader {
+	br := bufio.NewReader(r)
+	peek, err := br.Peek(len(utf8BOM))
+	if err == nil && string(peek) == string(utf8BOM) {
+		br.Discard(len(utf8BOM))
+	}
+	return br
+}
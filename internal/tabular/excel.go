@@ -0,0 +1,187 @@
+package tabular
+
+import (
+	"fmt"
+
+	"sheetFmt/internal/xlsxheader"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// excelReader adapts excelize directly to the Reader interface. It doesn't
+// go through internal/excel.Editor: that package imports tabular for its
+// own CSV/TSV support, so tabular importing it back would be a cycle.
+// detectHeaderRow below routes through internal/xlsxheader, the same
+// table-aware detection internal/excel.Editor.DetectHeaderRow/DetectTables
+// use, so a .xlsx target format file gets identical header detection either
+// way regardless of which package opened it.
+type excelReader struct {
+	file         *excelize.File
+	sheet        string
+	headerRow    int
+	dataStartRow int
+}
+
+func newExcelReader(path, sheet string) (Reader, error) {
+	file, err := excelize.OpenFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %v", path, err)
+	}
+
+	if sheet == "" {
+		sheets := file.GetSheetList()
+		if len(sheets) == 0 {
+			file.Close()
+			return nil, fmt.Errorf("no sheets found in %s", path)
+		}
+		sheet = sheets[0]
+	}
+
+	headerRow, dataStartRow, err := detectHeaderRow(file, path, sheet)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &excelReader{file: file, sheet: sheet, headerRow: headerRow, dataStartRow: dataStartRow}, nil
+}
+
+func (r *excelReader) Headers() ([]string, error) {
+	rows, err := r.file.Rows(r.sheet)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rowIdx := 0
+	for rows.Next() {
+		rowIdx++
+		cols, err := rows.Columns()
+		if err != nil {
+			return nil, err
+		}
+		if rowIdx == r.headerRow {
+			return cols, nil
+		}
+	}
+	return []string{}, nil
+}
+
+func (r *excelReader) Rows() (RowIter, error) {
+	rows, err := r.file.Rows(r.sheet)
+	if err != nil {
+		return nil, err
+	}
+	return &excelRowIter{rows: rows, skip: r.dataStartRow - 1}, nil
+}
+
+func (r *excelReader) Close() error {
+	return r.file.Close()
+}
+
+// excelRowIter skips rows up to the detected data-start row (which is past
+// the header row, or past the header block for a multi-row table header),
+// then delegates straight to the underlying excelize row cursor.
+type excelRowIter struct {
+	rows   *excelize.Rows
+	skip   int
+	rowIdx int
+}
+
+func (it *excelRowIter) Next() bool {
+	for it.rows.Next() {
+		it.rowIdx++
+		if it.rowIdx <= it.skip {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func (it *excelRowIter) Columns() ([]string, error) {
+	return it.rows.Columns()
+}
+
+func (it *excelRowIter) Err() error {
+	return it.rows.Error()
+}
+
+func (it *excelRowIter) Close() error {
+	return it.rows.Close()
+}
+
+// detectHeaderRow finds the header and data-start row for sheet, preferring
+// a native Excel Table over the heuristic header scan — the same
+// precedence internal/excel/format.go's detectHeaderRow gives
+// FormatFileNative, so a target format file with a declared ListObject gets
+// its columns read from the same row through either path. When the sheet
+// declares more than one table, the first one is used; tabular.OpenReader
+// has no targetTableName parameter to disambiguate, unlike the format-all
+// path.
+func detectHeaderRow(file *excelize.File, path, sheet string) (headerRow, dataStartRow int, err error) {
+	tables, err := xlsxheader.DetectTables(file, path, sheet)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read tables on sheet %s: %v", sheet, err)
+	}
+	if len(tables) > 0 {
+		return tables[0].HeaderRow, tables[0].DataStartRow, nil
+	}
+	row, err := xlsxheader.DetectHeaderRow(file, sheet)
+	if err != nil {
+		return 0, 0, err
+	}
+	return row, row + 1, nil
+}
+
+// excelWriter adapts a fresh excelize workbook to the Writer interface.
+type excelWriter struct {
+	file  *excelize.File
+	sheet string
+	path  string
+	row   int
+}
+
+func newExcelWriter(path, sheet string) (Writer, error) {
+	file := excelize.NewFile()
+	if sheet != "" && sheet != "Sheet1" {
+		if _, err := file.NewSheet(sheet); err != nil {
+			return nil, fmt.Errorf("failed to create sheet %s: %v", sheet, err)
+		}
+	} else {
+		sheet = "Sheet1"
+	}
+	return &excelWriter{file: file, sheet: sheet, path: path}, nil
+}
+
+func (w *excelWriter) WriteHeader(headers []string) error {
+	return w.writeRow(headers)
+}
+
+func (w *excelWriter) WriteRow(row []string) error {
+	return w.writeRow(row)
+}
+
+func (w *excelWriter) writeRow(values []string) error {
+	w.row++
+	for col, value := range values {
+		cell := fmt.Sprintf("%s%d", columnLetter(col), w.row)
+		if err := w.file.SetCellValue(w.sheet, cell, value); err != nil {
+			return fmt.Errorf("failed to write cell %s: %v", cell, err)
+		}
+	}
+	return nil
+}
+
+func (w *excelWriter) Close() error {
+	return w.file.SaveAs(w.path)
+}
+
+func columnLetter(index int) string {
+	result := ""
+	for index >= 0 {
+		result = string(rune('A'+index%26)) + result
+		index = index/26 - 1
+	}
+	return result
+}
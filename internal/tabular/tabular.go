@@ -0,0 +1,108 @@
+// Package tabular provides a thin Reader/Writer abstraction over tabular
+// data so the rest of sheetFmt (scanning, mapping, formatting) can work with
+// XLSX and CSV/TSV files interchangeably instead of branching on extension
+// everywhere.
+package tabular
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Reader streams header and data rows out of a tabular file, regardless of
+// its underlying format.
+type Reader interface {
+	// Headers returns the column headers (the detected header row for
+	// XLSX, the first row for CSV/TSV).
+	Headers() ([]string, error)
+	// Rows returns an iterator over the data rows that follow the header.
+	Rows() (RowIter, error)
+	Close() error
+}
+
+// RowIter streams data rows one at a time.
+type RowIter interface {
+	Next() bool
+	Columns() ([]string, error)
+	Err() error
+	Close() error
+}
+
+// Writer emits a header row followed by data rows to a tabular file.
+type Writer interface {
+	WriteHeader(headers []string) error
+	WriteRow(row []string) error
+	Close() error
+}
+
+// Format identifies the on-disk representation of a tabular file.
+type Format string
+
+const (
+	FormatXLSX Format = "xlsx"
+	FormatCSV  Format = "csv"
+	FormatTSV  Format = "tsv"
+)
+
+// DetectFormat infers a Format from a file extension (case-insensitive).
+func DetectFormat(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".xlsx":
+		return FormatXLSX, nil
+	case ".csv":
+		return FormatCSV, nil
+	case ".tsv":
+		return FormatTSV, nil
+	default:
+		return "", fmt.Errorf("unsupported tabular file extension: %s", path)
+	}
+}
+
+// CSVOptions configures the CSV/TSV adapter.
+type CSVOptions struct {
+	// Delimiter defaults to ',' for CSV and '\t' for TSV.
+	Delimiter rune
+	// QuoteAware records that quoted fields may contain the delimiter or
+	// newlines. encoding/csv always supports this, and the CSV reader and
+	// writer here never read this field, so setting it changes nothing.
+	QuoteAware bool
+	// StripBOM strips a leading UTF-8 byte-order-mark, which Excel
+	// commonly writes when exporting CSV.
+	StripBOM bool
+}
+
+// OpenReader opens path and returns a Reader for its detected format, sheet
+// is only used for XLSX inputs.
+func OpenReader(path, sheet string) (Reader, error) {
+	format, err := DetectFormat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case FormatXLSX:
+		return newExcelReader(path, sheet)
+	case FormatCSV:
+		return newCSVReader(path, CSVOptions{Delimiter: ',', StripBOM: true})
+	case FormatTSV:
+		return newCSVReader(path, CSVOptions{Delimiter: '\t', StripBOM: true})
+	default:
+		return nil, fmt.Errorf("unsupported tabular format: %s", format)
+	}
+}
+
+// NewWriter creates path and returns a Writer for the given format. sheet is
+// only used for XLSX outputs.
+func NewWriter(path string, format Format, sheet string) (Writer, error) {
+	switch format {
+	case FormatXLSX:
+		return newExcelWriter(path, sheet)
+	case FormatCSV:
+		return newCSVWriter(path, CSVOptions{Delimiter: ',', StripBOM: false})
+	case FormatTSV:
+		return newCSVWriter(path, CSVOptions{Delimiter: '\t', StripBOM: false})
+	default:
+		return nil, fmt.Errorf("unsupported tabular format: %s", format)
+	}
+}
@@ -0,0 +1,214 @@
+// Package xlsxheader holds the native-Excel-Table-aware header detection
+// that both internal/excel and internal/tabular need. It lives here, rather
+// than in either of those packages, because internal/excel already imports
+// internal/tabular for CSV/TSV support; internal/tabular importing it back
+// for header detection would be a cycle.
+package xlsxheader
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Table describes a native Excel Table (ListObject) found on a sheet, as
+// opposed to a heuristically-guessed header row.
+type Table struct {
+	Sheet          string
+	Name           string
+	Range          string
+	Headers        []string
+	HeaderRow      int
+	HeaderRowCount int
+	DataStartRow   int
+}
+
+// DetectTables returns every native Excel Table defined on sheet. path is
+// the workbook's file path, needed alongside the already-open file because
+// excelize's GetTables doesn't surface headerRowCount, so the table parts
+// are read a second time directly out of the zip container.
+func DetectTables(file *excelize.File, path, sheet string) ([]Table, error) {
+	tables, err := file.GetTables(sheet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tables on sheet %s: %v", sheet, err)
+	}
+
+	headerRowCounts, err := tableHeaderRowCounts(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read table header row counts: %v", err)
+	}
+
+	var detected []Table
+	for _, t := range tables {
+		startRow, _, err := splitCellRange(t.Range)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse range for table %s: %v", t.Name, err)
+		}
+
+		// headerRowCount defaults to 1 per the OOXML spec when the table
+		// part doesn't declare it explicitly; it can also be 0 (headerless
+		// table) or greater than 1 (a stacked multi-row header).
+		headerRows := headerRowCounts[t.Name]
+
+		var headers []string
+		if headerRows > 0 {
+			headers, err = readRowAt(file, sheet, startRow)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read header row for table %s: %v", t.Name, err)
+			}
+		}
+
+		detected = append(detected, Table{
+			Sheet:          sheet,
+			Name:           t.Name,
+			Range:          t.Range,
+			Headers:        headers,
+			HeaderRow:      startRow,
+			HeaderRowCount: headerRows,
+			DataStartRow:   startRow + headerRows,
+		})
+	}
+	return detected, nil
+}
+
+// xlsxTableHeader is the minimal shape of a table part's root element we
+// need: excelize's own GetTables doesn't surface headerRowCount on its
+// public Table type, even though it decodes the very same attribute
+// internally, so we read the XML part a second time ourselves.
+type xlsxTableHeader struct {
+	Name           string `xml:"name,attr"`
+	HeaderRowCount *int   `xml:"headerRowCount,attr"`
+}
+
+// tableHeaderRowCounts reads every xl/tables/table*.xml part directly out
+// of the workbook's zip container and returns each table's declared
+// headerRowCount, keyed by table name, defaulting absent attributes to 1.
+func tableHeaderRowCounts(path string) (map[string]int, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s as zip: %v", path, err)
+	}
+	defer zr.Close()
+
+	counts := make(map[string]int)
+	for _, f := range zr.File {
+		if !strings.HasPrefix(f.Name, "xl/tables/table") || !strings.HasSuffix(f.Name, ".xml") {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %v", f.Name, err)
+		}
+		var hdr xlsxTableHeader
+		decErr := xml.NewDecoder(rc).Decode(&hdr)
+		rc.Close()
+		if decErr != nil && decErr != io.EOF {
+			return nil, fmt.Errorf("failed to parse %s: %v", f.Name, decErr)
+		}
+
+		headerRows := 1
+		if hdr.HeaderRowCount != nil {
+			headerRows = *hdr.HeaderRowCount
+		}
+		counts[hdr.Name] = headerRows
+	}
+	return counts, nil
+}
+
+// readRowAt streams sheet up to row (1-based) and returns its cell values.
+func readRowAt(file *excelize.File, sheet string, row int) ([]string, error) {
+	rows, err := file.Rows(sheet)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	current := 0
+	for rows.Next() {
+		current++
+		cols, err := rows.Columns()
+		if err != nil {
+			return nil, err
+		}
+		if current == row {
+			return cols, nil
+		}
+	}
+	if err := rows.Error(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("row %d not found on sheet %s", row, sheet)
+}
+
+// splitCellRange parses an excelize range string such as "A1:D10" and
+// returns its start and end row numbers (1-based).
+func splitCellRange(rangeStr string) (startRow, endRow int, err error) {
+	parts := strings.Split(rangeStr, ":")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid range %q", rangeStr)
+	}
+
+	_, startRow, err = excelize.CellNameToCoordinates(parts[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	_, endRow, err = excelize.CellNameToCoordinates(parts[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	return startRow, endRow, nil
+}
+
+// DetectHeaderRow finds the row containing column headers using the
+// heuristic strategy: find the rightmost column with data anywhere in the
+// sheet, then find the first row that reaches it. It streams the sheet once
+// instead of loading every row into memory. Callers should prefer
+// DetectTables when the sheet declares a native Table; this is the fallback
+// for sheets that don't.
+func DetectHeaderRow(file *excelize.File, sheet string) (int, error) {
+	rows, err := file.Rows(sheet)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	rightmostCol := 0
+	var rowsWithRightmost []int
+	rowIdx := 0
+
+	for rows.Next() {
+		rowIdx++
+		row, err := rows.Columns()
+		if err != nil {
+			return 0, fmt.Errorf("failed to read row %d: %v", rowIdx, err)
+		}
+
+		for colIdx := len(row) - 1; colIdx >= 0; colIdx-- {
+			if strings.TrimSpace(row[colIdx]) != "" {
+				col := colIdx + 1 // convert to 1-based column
+				if col > rightmostCol {
+					rightmostCol = col
+					rowsWithRightmost = rowsWithRightmost[:0]
+				}
+				if col == rightmostCol {
+					rowsWithRightmost = append(rowsWithRightmost, rowIdx)
+				}
+				break // found the rightmost data in this row
+			}
+		}
+	}
+	if err := rows.Error(); err != nil {
+		return 0, fmt.Errorf("failed to stream rows: %v", err)
+	}
+
+	if rightmostCol == 0 || len(rowsWithRightmost) == 0 {
+		return 1, nil // default to row 1 if no data
+	}
+
+	return rowsWithRightmost[0], nil
+}